@@ -1,57 +1,173 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/andygrunwald/go-jira"
 	"github.com/catouc/jiwa/internal/commands"
 	"github.com/catouc/jiwa/internal/editor"
 	"github.com/catouc/jiwa/internal/jiwa"
+	"github.com/catouc/jiwa/internal/mirror"
+	"github.com/catouc/jiwa/internal/oplog"
 	flag "github.com/spf13/pflag"
 )
 
+// global holds the flags that go before the command.
 var (
-	cat       = flag.NewFlagSet("cat", flag.ContinueOnError)
-	comment   = flag.NewFlagSet("comment", flag.ContinueOnError)
-	create    = flag.NewFlagSet("create", flag.ContinueOnError)
-	edit      = flag.NewFlagSet("edit", flag.ContinueOnError)
-	issueType = flag.NewFlagSet("issue-type", flag.ContinueOnError)
-	label     = flag.NewFlagSet("label", flag.ContinueOnError)
-	list      = flag.NewFlagSet("list", flag.ContinueOnError)
-	move      = flag.NewFlagSet("move", flag.ContinueOnError)
-	reassign  = flag.NewFlagSet("reassign", flag.ContinueOnError)
-	search    = flag.NewFlagSet("search", flag.ContinueOnError)
+	global = flag.NewFlagSet("jiwa", flag.ContinueOnError)
+
+	offline = global.Bool("offline", false, "Answer cat, list and grep from the mirror created by \"jiwa sync\"")
+	dryRun  = global.Bool("dry-run", false, "Print the requests that would change Jira instead of sending them")
+	verbose = global.Bool("verbose", false, "Log every request and response to stderr, also enabled by setting JIWA_DEBUG")
+	harFile = global.String("har", "", "Write a HAR archive of all requests to the file")
+)
+
+var (
+	attach      = flag.NewFlagSet("attach", flag.ContinueOnError)
+	attachments = flag.NewFlagSet("attachments", flag.ContinueOnError)
+	boards      = flag.NewFlagSet("boards", flag.ContinueOnError)
+	cache       = flag.NewFlagSet("cache", flag.ContinueOnError)
+	cat         = flag.NewFlagSet("cat", flag.ContinueOnError)
+	comment     = flag.NewFlagSet("comment", flag.ContinueOnError)
+	config      = flag.NewFlagSet("config", flag.ContinueOnError)
+	create      = flag.NewFlagSet("create", flag.ContinueOnError)
+	edit        = flag.NewFlagSet("edit", flag.ContinueOnError)
+	fields      = flag.NewFlagSet("fields", flag.ContinueOnError)
+	grep        = flag.NewFlagSet("grep", flag.ContinueOnError)
+	issueType   = flag.NewFlagSet("issue-type", flag.ContinueOnError)
+	label       = flag.NewFlagSet("label", flag.ContinueOnError)
+	link        = flag.NewFlagSet("link", flag.ContinueOnError)
+	list        = flag.NewFlagSet("list", flag.ContinueOnError)
+	logWork     = flag.NewFlagSet("log", flag.ContinueOnError)
+	move        = flag.NewFlagSet("move", flag.ContinueOnError)
+	reassign    = flag.NewFlagSet("reassign", flag.ContinueOnError)
+	login       = flag.NewFlagSet("login", flag.ContinueOnError)
+	search      = flag.NewFlagSet("search", flag.ContinueOnError)
+	serverInfo  = flag.NewFlagSet("server-info", flag.ContinueOnError)
+	set         = flag.NewFlagSet("set", flag.ContinueOnError)
+	sprint      = flag.NewFlagSet("sprint", flag.ContinueOnError)
+	sprints     = flag.NewFlagSet("sprints", flag.ContinueOnError)
+	subtasks    = flag.NewFlagSet("subtasks", flag.ContinueOnError)
+	sync        = flag.NewFlagSet("sync", flag.ContinueOnError)
+	undo        = flag.NewFlagSet("undo", flag.ContinueOnError)
+	unlink      = flag.NewFlagSet("unlink", flag.ContinueOnError)
+	unwatch     = flag.NewFlagSet("unwatch", flag.ContinueOnError)
+	user        = flag.NewFlagSet("user", flag.ContinueOnError)
+	watch       = flag.NewFlagSet("watch", flag.ContinueOnError)
+	worklogs    = flag.NewFlagSet("worklogs", flag.ContinueOnError)
+
+	attachName = attach.StringP("name", "n", "", "Set the file name of the attachment when reading it from stdin")
+
+	attachmentsOutput = attachments.StringP("output", "o", "", `Set the file to download the attachment to, defaults to the attachment's
+name, "-" writes to stdout`)
+
+	boardsProject = boards.StringP("project", "p", "", "Only list the boards of this project")
 
 	catComments = cat.BoolP("comments", "c", false, "Toggle to include comments in the printout or not")
+	catLinks    = cat.BoolP("links", "l", false, "Toggle to include linked issues in the printout or not")
+	catWatchers = cat.BoolP("watchers", "w", false, "Toggle to include the watchers in the printout or not")
+
+	commentBulk     = bulkFlags(comment)
+	commentMarkdown = comment.Bool("markdown", false, "Treat the comment as markdown and convert it to Jira markup")
 
 	createProject = create.StringP("project", "p", "", `Set the project to create the ticket in, if not set it will default to your
 configured "defaultProject"`)
 	createFile       = create.StringP("file", "f", "", "Point to a file that contains your ticket")
 	createTicketType = create.StringP("ticket-type", "t", "Task", "Sets the type of ticket to open, defaults to \"Task\"")
 	createComponent  = create.StringP("component", "c", "", "Set the component of your ticket")
+	createParent     = create.String("parent", "", "Create the ticket as a sub-task of this issue")
+	createSubtask    = create.BoolP("subtask", "s", false, "Read the parent issue of the sub-task from stdin")
+	createMarkdown   = create.Bool("markdown", false, "Treat the description as markdown and convert it to Jira markup")
+	createFields     = create.StringArrayP("field", "F", nil, "Set a field like \"Story Points=5\", can be given multiple times")
+	createPrompt     = create.Bool("prompt", false, "Ask for required fields that are missing instead of failing")
+	createBulk       = create.Bool("bulk", false, "Create a ticket for every document in the file or stdin, documents are separated by \"---\" lines")
+
+	editMarkdown = edit.Bool("markdown", false, "Edit the description as markdown, converting from and to Jira markup")
+
+	fieldsCustom = fields.BoolP("custom", "c", false, "Only list custom fields")
+
+	grepProject = grep.StringP("project", "p", "", "Only search this project, by default all mirrored projects are searched")
+	grepOut     = grep.StringP("output", "o", "raw", "Set the output to be either \"raw\" for piping or \"table\" for nice formatting")
+
+	labelBulk = bulkFlags(label)
+
+	moveBulk = bulkFlags(move)
+
+	reassignBulk = bulkFlags(reassign)
 
 	listUser    = list.StringP("user", "u", "", "Set the user name to use in the list call, use \"empty\" to list unassigned tickets")
 	listStatus  = list.StringP("status", "s", "to do", "Set the status of the tickets you want to see")
 	listProject = list.StringP("project", "p", "", "Set the project to search in")
 	listOut     = list.StringP("output", "o", "raw", "Set the output to be either \"raw\" for piping or \"table\" for nice formatting")
 	listLabels  = list.StringArrayP("label", "l", nil, "Search for specific labels, all labels are joined by an OR")
+
+	listLimit    = list.IntP("limit", "n", 0, "Set the maximum amount of tickets to list, 0 lists all of them")
+	listPageSize = list.Int("page-size", 0, "Set how many tickets are fetched per request, 0 uses the server default")
+
+	sprintDuration = sprint.StringP("duration", "d", "2w", "Set how long a sprint runs when starting it without planned dates")
+
+	sprintsState = sprints.StringP("state", "s", "", "Only list sprints in this state, one of \"active\", \"future\" or \"closed\"")
+
+	subtasksOut = subtasks.StringP("output", "o", "raw", "Set the output to be either \"raw\" for piping or \"table\" for nice formatting")
+
+	syncFull = sync.Bool("full", false, "Fetch all issues again and drop the ones that were deleted or moved to another project")
+
+	undoList = undo.BoolP("list", "l", false, "List the operations that can be undone instead")
+
+	unlinkType = unlink.StringP("type", "t", "", "Only remove links of this type, by default all links between the issues are removed")
+
+	logStarted = logWork.String("started", "", `Set when the work was started, like "2006-01-02 15:04" or "09:30" for today,
+defaults to now`)
+
+	searchLimit    = search.IntP("limit", "n", 0, "Set the maximum amount of tickets to return, 0 returns all of them")
+	searchPageSize = search.Int("page-size", 0, "Set how many tickets are fetched per request, 0 uses the server default")
 )
 
+// bulkFlags adds the flags controlling commands that work through many
+// issues to the flag set.
+func bulkFlags(fs *flag.FlagSet) *commands.BulkOptions {
+	opts := &commands.BulkOptions{}
+	fs.IntVarP(&opts.Parallel, "parallel", "P", 1, "Set how many issues are worked on at the same time")
+	fs.BoolVar(&opts.ContinueOnError, "continue-on-error", false, "Keep going when an issue fails instead of skipping the remaining ones")
+	return opts
+}
+
 var cfg commands.Config
 
+// locations of the config file and the file its encrypted secrets live in
+var cfgFileLoc, secretsFileLoc string
+
+// credentialsFromHelper is set when the credential helper provided the
+// secret, so it can be told whether it worked.
+var credentialsFromHelper bool
+
 func init() {
+	global.SetInterspersed(false)
+	err := global.Parse(os.Args[1:])
+	if err != nil {
+		fmt.Println("Usage: jiwa [--offline] [--dry-run] [--verbose] [--har <file>] <command>")
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], global.Args()...)
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		fmt.Printf("cannot locate user home dir, is `$HOME` set? Detailed error: %s\n", err)
 		os.Exit(1)
 	}
 
-	cfgFileLoc := path.Join(homeDir, ".config", "jiwa", "config.json")
+	cfgFileLoc = path.Join(homeDir, ".config", "jiwa", "config.json")
+	secretsFileLoc = path.Join(homeDir, ".config", "jiwa", "secrets.enc")
 
 	cfgBytes, err := os.ReadFile(cfgFileLoc)
 	if err != nil {
@@ -65,6 +181,49 @@ func init() {
 		os.Exit(1)
 	}
 
+	if cfg.OAuth2.TokenFile == "" {
+		cfg.OAuth2.TokenFile = path.Join(homeDir, ".config", "jiwa", "oauth2-token.json")
+	}
+
+	if cfg.Cache.Dir == "" {
+		cfg.Cache.Dir, err = jiwa.DefaultCacheDir()
+		if err != nil && cfg.Cache.Enabled {
+			fmt.Printf("failed to set up the cache: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.MirrorDir == "" {
+		cfg.MirrorDir, err = mirror.DefaultDir()
+		if err != nil {
+			fmt.Printf("failed to locate the mirror: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.UndoDir == "" {
+		cfg.UndoDir, err = oplog.DefaultDir()
+		if err != nil {
+			fmt.Printf("failed to locate the operation log: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	_, err = os.Stat(secretsFileLoc)
+	if err == nil {
+		passphrase, err := commands.ReadPassphrase("Passphrase for jiwa's secrets: ")
+		if err != nil {
+			fmt.Printf("failed to get passphrase: %s\n", err)
+			os.Exit(1)
+		}
+
+		err = cfg.LoadSecrets(secretsFileLoc, passphrase)
+		if err != nil {
+			fmt.Printf("failed to load secrets: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	username, set := os.LookupEnv("JIWA_USERNAME")
 	if set {
 		cfg.Username = username
@@ -78,25 +237,44 @@ func init() {
 		cfg.Token = token
 	}
 
-	valid := cfg.IsValid()
+	// the mirror is read without talking to Jira, so there's no need
+	// for credentials
+	if !*offline {
+		credentialsFromHelper, err = cfg.LoadCredentials(context.TODO())
+		if err != nil {
+			fmt.Printf("failed to get credentials from the credential helper: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	valid := cfg.IsValid() || (*offline && cfg.BaseURL != "")
 	if !valid {
 		fmt.Printf(`Config is missing important values, \"baseURL\" and \"username\" + \"password\" or \"token\" need to be set.
 "username", "password" and "token" can be configured through their respective environment variables "JIWA_USERNAME", "JIWA_PASSWORD" and "JIWA_TOKEN".
+With "auth" set to "pat" only "token" is needed, with "oauth2" "oauth2.clientId" and "oauth2.clientSecret".
 The configuration file is located at %s
 `, cfgFileLoc)
 		os.Exit(1)
 	}
 
-	if cfg.APIVersion == "" {
-		cfg.APIVersion = "2"
-	}
-
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 5 * time.Second
 	}
 
+	if cfg.Retry.MaxAttempts == 0 {
+		cfg.Retry.MaxAttempts = 3
+	}
+
+	if cfg.Retry.BaseDelay == 0 {
+		cfg.Retry.BaseDelay = 500 * time.Millisecond
+	}
+
+	if cfg.Retry.MaxDelay == 0 {
+		cfg.Retry.MaxDelay = 30 * time.Second
+	}
+
 	if len(os.Args) < 2 {
-		fmt.Printf("Usage: jiwa {cat|comment|create|edit|issueType||label|list|move|reassign|search}\n")
+		fmt.Printf("Usage: jiwa {attach|attachments|boards|cache|cat|comment|config|create|edit|fields|grep|issue-type|label|link|list|log|login|move|reassign|search|server-info|set|sprint|sprints|subtasks|sync|undo|unlink|unwatch|user|watch|worklogs}\n")
 		os.Exit(1)
 	}
 
@@ -106,20 +284,200 @@ func main() {
 	httpClient := http.DefaultClient
 	httpClient.Timeout = cfg.Timeout
 
-	c := jiwa.Client{
-		Username:   cfg.Username,
-		Password:   cfg.Password,
-		Token:      cfg.Token,
-		BaseURL:    cfg.BaseURL + "/" + cfg.ReturnCleanEndpointPrefix(),
-		APIVersion: cfg.APIVersion,
-		HTTPClient: httpClient,
+	auth, err := cfg.Authenticator(httpClient)
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+
+	// logging in is what makes the API URL known with OAuth 2.0
+	baseURL, err := cfg.APIBaseURL()
+	if err != nil && os.Args[1] != "login" && !*offline {
+		printError(err)
+		os.Exit(1)
+	}
+
+	c := &jiwa.Client{
+		Auth:           auth,
+		BaseURL:        baseURL,
+		APIVersion:     cfg.APIVersion,
+		DeploymentType: cfg.DeploymentType,
+		HTTPClient:     httpClient,
+		RetryPolicy:    cfg.Retry,
+		DryRun:         *dryRun,
+	}
+	if cfg.Cache.Enabled {
+		c.Cache = &cfg.Cache.Cache
+	}
+
+	if *verbose || os.Getenv("JIWA_DEBUG") != "" {
+		c.Trace = &jiwa.Trace{Log: os.Stderr}
+	}
+	if *harFile != "" {
+		if c.Trace == nil {
+			c.Trace = &jiwa.Trace{}
+		}
+		c.Trace.HARFile = *harFile
+
+		// an empty archive up front tells whether the file can be written
+		err = c.Trace.WriteHAR()
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	}
+
+	cmd := commands.Command{Client: c, Config: cfg, Offline: *offline}
+
+	if *offline {
+		switch os.Args[1] {
+		case "cat", "grep", "list", "ls":
+		default:
+			fmt.Fprintf(os.Stderr, "jiwa %s is not available offline, only cat, grep and list are\n", os.Args[1])
+			os.Exit(1)
+		}
 	}
 
-	cmd := commands.Command{Client: c, Config: cfg}
+	// logging in is what makes OAuth 2.0 credentials usable in the first
+	// place
+	if credentialsFromHelper && os.Args[1] != "login" {
+		c.Authorized = func(ok bool) {
+			err := cfg.ReportCredentials(context.TODO(), ok)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	}
 
 	stat, _ := os.Stdin.Stat()
 
 	switch os.Args[1] {
+	case "attach":
+		err := attach.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("Usage: jiwa attach <issue-id> <file>...")
+			fmt.Println("echo \"<issue-id>\" | jiwa attach <file>...")
+			fmt.Println("cat <file> | jiwa attach <issue-id> --name <file-name>")
+			os.Exit(1)
+		}
+
+		piped := (stat.Mode() & os.ModeCharDevice) == 0
+
+		var issues, files []string
+		switch {
+		case piped && attach.NArg() > 0 && cmd.StripBaseURL(attach.Arg(0)) == "":
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+			files = attach.Args()
+		case attach.NArg() > 0:
+			issues = []string{cmd.StripBaseURL(attach.Arg(0))}
+			files = attach.Args()[1:]
+		default:
+			fmt.Println("Usage: jiwa attach <issue-id> <file>...")
+			os.Exit(1)
+		}
+
+		if len(files) == 0 {
+			if !piped {
+				fmt.Println("Usage: jiwa attach <issue-id> <file>...")
+				os.Exit(1)
+			}
+
+			err = cmd.AttachReader(issues[0], *attachName, os.Stdin)
+		} else {
+			_, err = cmd.Attach(issues, files)
+		}
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range issues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+	case "attachments":
+		err := attachments.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("Usage: jiwa attachments <issue-id>")
+			fmt.Println("jiwa attachments get <issue-id> <attachment-id|file-name>")
+			os.Exit(1)
+		}
+
+		if attachments.Arg(0) == "get" {
+			if attachments.NArg() != 3 {
+				fmt.Println("Usage: jiwa attachments get <issue-id> <attachment-id|file-name>")
+				os.Exit(1)
+			}
+
+			attachment, content, err := cmd.DownloadAttachment(cmd.StripBaseURL(attachments.Arg(1)), attachments.Arg(2))
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+			defer content.Close()
+
+			out := os.Stdout
+			if *attachmentsOutput != "-" {
+				target := *attachmentsOutput
+				if target == "" {
+					target = path.Base(attachment.Filename)
+				}
+
+				out, err = os.Create(target)
+				if err != nil {
+					printError(err)
+					os.Exit(1)
+				}
+				defer out.Close()
+			}
+
+			_, err = io.Copy(out, content)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if attachments.NArg() != 1 {
+			fmt.Println("Usage: jiwa attachments <issue-id>")
+			os.Exit(1)
+		}
+
+		issueAttachments, err := cmd.Attachments(cmd.StripBaseURL(attachments.Arg(0)))
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+		fmt.Fprintf(w, "ID\tName\tSize\tCreated\n")
+		for _, a := range issueAttachments {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", a.ID, a.Filename, a.Size, a.Created)
+		}
+		w.Flush()
+	case "boards":
+		err := boards.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("Usage: jiwa boards [--project]")
+			os.Exit(1)
+		}
+
+		projectBoards, err := cmd.Boards(*boardsProject)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintf(w, "ID\tName\tType\n")
+		for _, b := range projectBoards {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", b.ID, b.Name, b.Type)
+		}
+		w.Flush()
 	case "cat":
 		err := cat.Parse(os.Args[2:])
 		if err != nil {
@@ -132,7 +490,7 @@ func main() {
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 		} else {
@@ -146,15 +504,42 @@ func main() {
 
 		issue, err := cmd.Cat(issues[0])
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
-		fmt.Println(issue.Fields.Summary+"\n"+issue.Fields.Description, nil)
+		fmt.Println(issue.Fields.Summary + "\n" + issue.Fields.Description)
 
-		if *catComments {
+		if *catComments && issue.Fields.Comments != nil {
 			for _, comment := range issue.Fields.Comments.Comments {
-				fmt.Printf("%s wrote on %s:\n%s\n", comment.Author.Name, comment.Created, comment.Body)
+				author := comment.Author.DisplayName
+				if author == "" {
+					author = comment.Author.Name
+				}
+				fmt.Printf("%s wrote on %s:\n%s\n", author, comment.Created, comment.Body)
+			}
+		}
+
+		if *catWatchers {
+			watchers, err := cmd.Watchers(issues[0])
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			for _, w := range watchers {
+				fmt.Printf("watched by %s\n", w.DisplayName)
+			}
+		}
+
+		if *catLinks {
+			for _, l := range issue.Fields.IssueLinks {
+				switch {
+				case l.OutwardIssue != nil:
+					fmt.Printf("%s %s\n", l.Type.Outward, cmd.ConstructIssueURL(l.OutwardIssue.Key))
+				case l.InwardIssue != nil:
+					fmt.Printf("%s %s\n", l.Type.Inward, cmd.ConstructIssueURL(l.InwardIssue.Key))
+				}
 			}
 		}
 	case "comment":
@@ -176,7 +561,7 @@ func main() {
 
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 
@@ -185,14 +570,14 @@ func main() {
 			} else {
 				scanner, cleanup, err := editor.SetupTmpFileWithEditor("")	
 				if err != nil {
-					fmt.Println(err)
+					printError(err)
 					os.Exit(1)					
 				}
 				defer cleanup()
 
 				text, err := commands.BuildCommentFromScanner(scanner)
 				if err != nil {
-					fmt.Println(err)
+					printError(err)
 					os.Exit(1)
 				}
 				commentStr = text
@@ -209,14 +594,14 @@ func main() {
 			case 1:
 				scanner, cleanup, err := editor.SetupTmpFileWithEditor("")	
 				if err != nil {
-					fmt.Println(err)
+					printError(err)
 					os.Exit(1)					
 				}
 				defer cleanup()
 
 				text, err := commands.BuildCommentFromScanner(scanner)
 				if err != nil {
-					fmt.Println(err)
+					printError(err)
 					os.Exit(1)
 				}
 				commentStr = text
@@ -227,14 +612,84 @@ func main() {
 			issues = []string{cmd.StripBaseURL(comment.Arg(0))}
 		}
 
+		if *commentMarkdown {
+			cmd.Config.Markdown = true
+		}
+
+		cmd.Bulk = *commentBulk
 		commentedIssues, err := cmd.Comment(issues, commentStr)
+		for _, issue := range commentedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "cache":
+		err := cache.Parse(os.Args[2:])
+		if err != nil || cache.NArg() != 1 || cache.Arg(0) != "clear" {
+			fmt.Println("Usage: jiwa cache clear")
+			os.Exit(1)
+		}
+
+		err = cfg.Cache.Clear()
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
+			os.Exit(1)
+		}
+	case "config":
+		err := config.Parse(os.Args[2:])
+		if err != nil || config.NArg() != 1 {
+			fmt.Println("Usage: jiwa config {encrypt|decrypt}")
 			os.Exit(1)
 		}
 
-		for _, issue := range commentedIssues {
-			fmt.Println(cmd.ConstructIssueURL(issue))
+		switch config.Arg(0) {
+		case "encrypt":
+			passphrase := cfg.Passphrase
+			if passphrase == nil {
+				passphrase, err = commands.ReadPassphrase("New passphrase: ")
+				if err != nil {
+					printError(err)
+					os.Exit(1)
+				}
+
+				repeated, err := commands.ReadPassphrase("Repeat passphrase: ")
+				if err != nil {
+					printError(err)
+					os.Exit(1)
+				}
+
+				if string(passphrase) != string(repeated) {
+					fmt.Fprintln(os.Stderr, "the passphrases don't match")
+					os.Exit(1)
+				}
+			}
+
+			err = cfg.EncryptConfig(cfgFileLoc, secretsFileLoc, passphrase)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(os.Stderr, "Secrets moved to %s\n", secretsFileLoc)
+		case "decrypt":
+			if cfg.Passphrase == nil {
+				fmt.Fprintln(os.Stderr, "the secrets are not encrypted")
+				os.Exit(1)
+			}
+
+			err = cfg.DecryptConfig(cfgFileLoc, secretsFileLoc, cfg.Passphrase)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(os.Stderr, "Secrets moved back to %s\n", cfgFileLoc)
+		default:
+			fmt.Println("Usage: jiwa config {encrypt|decrypt}")
+			os.Exit(1)
 		}
 	case "create":
 		err := create.Parse(os.Args[2:])
@@ -245,17 +700,70 @@ func main() {
 
 		project, err := cmd.FishOutProject(*createProject)
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
-		key, err := cmd.Create(project, *createFile, *createTicketType, *createComponent)
+		if *createMarkdown {
+			cmd.Config.Markdown = true
+		}
+
+		createInput := commands.CreateInput{
+			Project:     project,
+			SrcFilePath: *createFile,
+			TicketType:  *createTicketType,
+			Component:   *createComponent,
+			Parent:      cmd.StripBaseURL(*createParent),
+			Fields:      *createFields,
+			Prompt:      *createPrompt,
+		}
+
+		if *createSubtask {
+			if (stat.Mode() & os.ModeCharDevice) != 0 {
+				fmt.Println("Usage: echo \"<issue-id>\" | jiwa create --subtask")
+				os.Exit(1)
+			}
+
+			parents, err := cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			if len(parents) != 1 {
+				fmt.Println("--subtask expects exactly one parent issue on stdin")
+				os.Exit(1)
+			}
+
+			createInput.Parent = parents[0]
+			createInput.StdinConsumed = true
+		}
+
+		// let Create pick the project's sub-task type unless one was asked for
+		if createInput.Parent != "" && !create.Changed("ticket-type") {
+			createInput.TicketType = ""
+		}
+
+		if *createBulk {
+			keys, err := cmd.CreateBulk(createInput)
+			for _, key := range keys {
+				printCreated(&cmd, key)
+			}
+
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		key, err := cmd.Create(createInput)
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
-		fmt.Println(cmd.ConstructIssueURL(key))
+		printCreated(&cmd, key)
 	case "edit":
 		err := edit.Parse(os.Args[2:])
 		if err != nil {
@@ -268,7 +776,7 @@ func main() {
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 		} else {
@@ -280,44 +788,99 @@ func main() {
 			issues = []string{cmd.StripBaseURL(edit.Arg(0))}
 		}
 
+		if *editMarkdown {
+			cmd.Config.Markdown = true
+		}
+
 		key, err := cmd.Edit(issues[0])
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
 		fmt.Println(cmd.ConstructIssueURL(key))
-	case "issue-type":
-		err := issueType.Parse(os.Args[2:])
+	case "fields":
+		err := fields.Parse(os.Args[2:])
 		if err != nil {
-			fmt.Println("jiwa issue-type <project-key>")
+			fmt.Println("Usage: jiwa fields [--custom]")
 			os.Exit(1)
 		}
 
-		if len(issueType.Args()) == 0 {
-			fmt.Println("jiwa issue-type <project-key>")
+		fieldList, err := cmd.Fields(*fieldsCustom)
+		if err != nil {
+			printError(err)
 			os.Exit(1)
 		}
 
-		issueTypes, err := cmd.IssueTypes(issueType.Arg(0))
-		if err != nil {
-			fmt.Println(err)
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+		fmt.Fprintf(w, "ID\tName\tType\n")
+		for _, f := range fieldList {
+			fieldType := f.Schema.Type
+			if f.Schema.Items != "" {
+				fieldType += "<" + f.Schema.Items + ">"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", f.ID, f.Name, fieldType)
+		}
+		w.Flush()
+	case "grep":
+		err := grep.Parse(os.Args[2:])
+		if err != nil || grep.NArg() == 0 {
+			fmt.Println("Usage: jiwa grep [--project] <text>")
 			os.Exit(1)
 		}
 
-		for _, it := range issueTypes {
-			fmt.Println(it.Name)
-		}
-	case "label":
-		err := label.Parse(os.Args[2:])
+		issues, err := cmd.Grep(strings.Join(grep.Args(), " "), *grepProject)
 		if err != nil {
-			fmt.Println("jiwa label <issue ID> <label> <label>...")
-			fmt.Println("echo \"<issue-id>\" | jiwa label <label> <label> ...")
+			printError(err)
 			os.Exit(1)
 		}
 
-		var labels []string
-		var issues []string
+		switch *grepOut {
+		case "raw":
+			for _, i := range issues {
+				fmt.Println(cmd.ConstructIssueURL(i.Key))
+			}
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+			fmt.Fprintf(w, "ID\tSummary\tURL\n")
+			for _, i := range issues {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", i.Key, i.Fields.Summary, cmd.ConstructIssueURL(i.Key))
+			}
+			w.Flush()
+		default:
+			fmt.Printf("Usage: jiwa grep --output [table|raw]")
+		}
+	case "issue-type":
+		err := issueType.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa issue-type <project-key>")
+			os.Exit(1)
+		}
+
+		if len(issueType.Args()) == 0 {
+			fmt.Println("jiwa issue-type <project-key>")
+			os.Exit(1)
+		}
+
+		issueTypes, err := cmd.IssueTypes(issueType.Arg(0))
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, it := range issueTypes {
+			fmt.Println(it.Name)
+		}
+	case "label":
+		err := label.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa label <issue ID> <label> <label>...")
+			fmt.Println("echo \"<issue-id>\" | jiwa label <label> <label> ...")
+			os.Exit(1)
+		}
+
+		var labels []string
+		var issues []string
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
 			if len(label.Args()) == 0 {
 				fmt.Println("Usage: jiwa label <label> <label> ...")
@@ -326,7 +889,7 @@ func main() {
 
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 
@@ -341,13 +904,58 @@ func main() {
 			labels = label.Args()[1:]
 		}
 
+		cmd.Bulk = *labelBulk
 		labelledIssues, err := cmd.Label(issues, labels)
+		for _, issue := range labelledIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	case "link":
+		err := link.Parse(os.Args[2:])
 		if err != nil {
-			fmt.Println(err)
+			fmt.Println("jiwa link <issue-id> <link-type> <issue-id>")
+			fmt.Println("echo \"<issue-id>\" | jiwa link <link-type> <issue-id>")
 			os.Exit(1)
 		}
 
-		for _, issue := range labelledIssues {
+		var linkType, target string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(link.Args()) != 2 {
+				fmt.Println("Usage: jiwa link <link-type> <issue-id>")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			linkType = link.Arg(0)
+			target = cmd.StripBaseURL(link.Arg(1))
+		} else {
+			if len(link.Args()) != 3 {
+				fmt.Println("Usage: jiwa link <issue-id> <link-type> <issue-id>")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(link.Arg(0))}
+			linkType = link.Arg(1)
+			target = cmd.StripBaseURL(link.Arg(2))
+		}
+
+		linkedIssues, err := cmd.Link(issues, linkType, target)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range linkedIssues {
 			fmt.Println(cmd.ConstructIssueURL(issue))
 		}
 	case "list":
@@ -362,10 +970,12 @@ func main() {
 			Project:  *listProject,
 			Status:   *listStatus,
 			Labels:   *listLabels,
+			Limit:    *listLimit,
+			PageSize: *listPageSize,
 		}
 		issues, err := cmd.List(listInput)
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
@@ -396,10 +1006,12 @@ func main() {
 			Project:  *listProject,
 			Status:   *listStatus,
 			Labels:   *listLabels,
+			Limit:    *listLimit,
+			PageSize: *listPageSize,
 		}
 		issues, err := cmd.List(listInput)
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
 
@@ -418,6 +1030,70 @@ func main() {
 		default:
 			fmt.Printf("Usage: jiwa ls --out [table|raw]")
 		}
+	case "log":
+		err := logWork.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa log <issue-id> <duration> [comment]")
+			fmt.Println("echo \"<issue-id>\" | jiwa log <duration> [comment]")
+			os.Exit(1)
+		}
+
+		var timeSpent, logComment string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(logWork.Args()) == 0 || len(logWork.Args()) > 2 {
+				fmt.Println("Usage: jiwa log <duration> [comment]")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			timeSpent = logWork.Arg(0)
+			logComment = logWork.Arg(1)
+		} else {
+			if len(logWork.Args()) < 2 || len(logWork.Args()) > 3 {
+				fmt.Println("Usage: jiwa log <issue-id> <duration> [comment]")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(logWork.Arg(0))}
+			timeSpent = logWork.Arg(1)
+			logComment = logWork.Arg(2)
+		}
+
+		started, err := commands.ParseStarted(*logStarted)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		loggedIssues, err := cmd.Log(issues, timeSpent, logComment, started)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range loggedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+	case "login":
+		err := login.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("Usage: jiwa login")
+			os.Exit(1)
+		}
+
+		err = cmd.Login()
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, "Logged in")
 	case "move":
 		err := move.Parse(os.Args[2:])
 		if err != nil {
@@ -436,7 +1112,7 @@ func main() {
 
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 
@@ -451,15 +1127,16 @@ func main() {
 			status = move.Arg(1)
 		}
 
+		cmd.Bulk = *moveBulk
 		movedIssues, err := cmd.Move(issues, status)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
 		for _, issue := range movedIssues {
 			fmt.Println(cmd.ConstructIssueURL(issue))
 		}
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	case "mv":
 		err := move.Parse(os.Args[2:])
 		if err != nil {
@@ -478,7 +1155,7 @@ func main() {
 
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 
@@ -493,15 +1170,16 @@ func main() {
 			status = move.Arg(1)
 		}
 
+		cmd.Bulk = *moveBulk
 		movedIssues, err := cmd.Move(issues, status)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
 		for _, issue := range movedIssues {
 			fmt.Println(cmd.ConstructIssueURL(issue))
 		}
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	case "reassign":
 		err := reassign.Parse(os.Args[2:])
 		if err != nil {
@@ -520,7 +1198,7 @@ func main() {
 
 			issues, err = cmd.ReadIssueListFromStdin()
 			if err != nil {
-				fmt.Println(err)
+				printError(err)
 				os.Exit(1)
 			}
 
@@ -535,13 +1213,484 @@ func main() {
 			user = reassign.Arg(1)
 		}
 
+		cmd.Bulk = *reassignBulk
 		reassignedIssues, err := cmd.Reassign(issues, user)
+		for _, issue := range reassignedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
+			os.Exit(1)
+		}
+	case "server-info":
+		err := serverInfo.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("Usage: jiwa server-info")
 			os.Exit(1)
 		}
 
-		for _, issue := range reassignedIssues {
+		info, err := cmd.ServerInfo()
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintf(w, "Title:\t%s\n", info.ServerTitle)
+		fmt.Fprintf(w, "Base URL:\t%s\n", info.BaseURL)
+		fmt.Fprintf(w, "Deployment:\t%s\n", info.DeploymentType)
+		fmt.Fprintf(w, "Version:\t%s\n", info.Version)
+		fmt.Fprintf(w, "Build:\t%d\n", info.BuildNumber)
+		w.Flush()
+	case "set":
+		err := set.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa set <issue-id> \"<field>=<value>\"...")
+			fmt.Println("echo \"<issue-id>\" | jiwa set \"<field>=<value>\"...")
+			os.Exit(1)
+		}
+
+		var assignments []string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(set.Args()) == 0 {
+				fmt.Println("Usage: jiwa set \"<field>=<value>\"...")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			assignments = set.Args()
+		} else {
+			if len(set.Args()) < 2 {
+				fmt.Println("Usage: jiwa set <issue-id> \"<field>=<value>\"...")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(set.Arg(0))}
+			assignments = set.Args()[1:]
+		}
+
+		updatedIssues, err := cmd.Set(issues, assignments)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range updatedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+	case "sprint":
+		err := sprint.Parse(os.Args[2:])
+		if err != nil || sprint.NArg() < 2 {
+			fmt.Println("Usage: jiwa sprint add <sprint-id> <issue-id>...")
+			fmt.Println("echo \"<issue-id>\" | jiwa sprint add <sprint-id>")
+			fmt.Println("jiwa sprint start <sprint-id> [--duration]")
+			fmt.Println("jiwa sprint close <sprint-id>")
+			os.Exit(1)
+		}
+
+		sprintID := sprint.Arg(1)
+		switch sprint.Arg(0) {
+		case "add":
+			var issues []string
+			if (stat.Mode() & os.ModeCharDevice) == 0 {
+				issues, err = cmd.ReadIssueListFromStdin()
+				if err != nil {
+					printError(err)
+					os.Exit(1)
+				}
+			} else {
+				if sprint.NArg() < 3 {
+					fmt.Println("Usage: jiwa sprint add <sprint-id> <issue-id>...")
+					os.Exit(1)
+				}
+
+				for _, arg := range sprint.Args()[2:] {
+					issues = append(issues, cmd.StripBaseURL(arg))
+				}
+			}
+
+			addedIssues, err := cmd.SprintAdd(issues, sprintID)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			for _, issue := range addedIssues {
+				fmt.Println(cmd.ConstructIssueURL(issue))
+			}
+		case "start":
+			duration, err := commands.ParseSprintDuration(*sprintDuration)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			err = cmd.SprintStart(sprintID, duration)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		case "close":
+			err = cmd.SprintClose(sprintID)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Println("Usage: jiwa sprint {add|start|close} <sprint-id>")
+			os.Exit(1)
+		}
+	case "sprints":
+		err := sprints.Parse(os.Args[2:])
+		if err != nil || sprints.NArg() != 1 {
+			fmt.Println("Usage: jiwa sprints <board-id|board-name> [--state]")
+			os.Exit(1)
+		}
+
+		boardSprints, err := cmd.Sprints(sprints.Arg(0), *sprintsState)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintf(w, "ID\tName\tState\tStart\tEnd\n")
+		for _, s := range boardSprints {
+			start, end := "", ""
+			if s.StartDate != nil {
+				start = s.StartDate.Local().Format("2006-01-02")
+			}
+			if s.EndDate != nil {
+				end = s.EndDate.Local().Format("2006-01-02")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.ID, s.Name, s.State, start, end)
+		}
+		w.Flush()
+	case "subtasks":
+		err := subtasks.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa subtasks <issue-id>")
+			fmt.Println("echo \"<issue-id>\" | jiwa subtasks")
+			os.Exit(1)
+		}
+
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		} else {
+			if len(subtasks.Args()) == 0 {
+				fmt.Println("Usage: jiwa subtasks <issue-id>")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(subtasks.Arg(0))}
+		}
+
+		var issueSubtasks []jira.Subtasks
+		for _, issue := range issues {
+			st, err := cmd.Subtasks(issue)
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+			issueSubtasks = append(issueSubtasks, st...)
+		}
+
+		switch *subtasksOut {
+		case "raw":
+			for _, st := range issueSubtasks {
+				fmt.Println(cmd.ConstructIssueURL(st.Key))
+			}
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+			fmt.Fprintf(w, "ID\tSummary\tStatus\tURL\n")
+			for _, st := range issueSubtasks {
+				status := ""
+				if st.Fields.Status != nil {
+					status = st.Fields.Status.Name
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.Key, st.Fields.Summary, status, cmd.ConstructIssueURL(st.Key))
+			}
+			w.Flush()
+		default:
+			fmt.Printf("Usage: jiwa subtasks --output [table|raw]")
+		}
+	case "sync":
+		err := sync.Parse(os.Args[2:])
+		if err != nil || sync.NArg() > 1 {
+			fmt.Println("Usage: jiwa sync [--full] [project]")
+			os.Exit(1)
+		}
+
+		project, err := cmd.FishOutProject(sync.Arg(0))
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		synced, err := cmd.Sync(project, *syncFull)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "Synced %d issues of %s\n", synced, project)
+	case "unwatch":
+		err := unwatch.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa unwatch <issue-id> [username]")
+			fmt.Println("echo \"<issue-id>\" | jiwa unwatch [username]")
+			os.Exit(1)
+		}
+
+		var user string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(unwatch.Args()) > 1 {
+				fmt.Println("Usage: jiwa unwatch [username]")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			user = unwatch.Arg(0)
+		} else {
+			if len(unwatch.Args()) == 0 || len(unwatch.Args()) > 2 {
+				fmt.Println("Usage: jiwa unwatch <issue-id> [username]")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(unwatch.Arg(0))}
+			user = unwatch.Arg(1)
+		}
+
+		unwatchedIssues, err := cmd.Unwatch(issues, user)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range unwatchedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+	case "user":
+		err := user.Parse(os.Args[2:])
+		if err != nil || user.NArg() != 1 {
+			fmt.Println("Usage: jiwa user <email|name|display-name>")
+			os.Exit(1)
+		}
+
+		users, err := cmd.Users(user.Arg(0))
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+		fmt.Fprintf(w, "ID\tName\tEmail\tActive\n")
+		for _, u := range users {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", cmd.Client.UserIdentity(context.TODO(), u), u.DisplayName, u.EmailAddress, u.Active)
+		}
+		w.Flush()
+	case "watch":
+		err := watch.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa watch <issue-id> [username]")
+			fmt.Println("echo \"<issue-id>\" | jiwa watch [username]")
+			os.Exit(1)
+		}
+
+		var user string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(watch.Args()) > 1 {
+				fmt.Println("Usage: jiwa watch [username]")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			user = watch.Arg(0)
+		} else {
+			if len(watch.Args()) == 0 || len(watch.Args()) > 2 {
+				fmt.Println("Usage: jiwa watch <issue-id> [username]")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(watch.Arg(0))}
+			user = watch.Arg(1)
+		}
+
+		watchedIssues, err := cmd.Watch(issues, user)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range watchedIssues {
+			fmt.Println(cmd.ConstructIssueURL(issue))
+		}
+	case "worklogs":
+		err := worklogs.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa worklogs <issue-id>")
+			fmt.Println("jiwa worklogs delete <issue-id> <worklog-id>")
+			os.Exit(1)
+		}
+
+		if worklogs.Arg(0) == "delete" {
+			if worklogs.NArg() != 3 {
+				fmt.Println("Usage: jiwa worklogs delete <issue-id> <worklog-id>")
+				os.Exit(1)
+			}
+
+			issue := cmd.StripBaseURL(worklogs.Arg(1))
+			err = cmd.DeleteWorklog(issue, worklogs.Arg(2))
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			fmt.Println(cmd.ConstructIssueURL(issue))
+			return
+		}
+
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		} else {
+			if worklogs.NArg() != 1 {
+				fmt.Println("Usage: jiwa worklogs <issue-id>")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(worklogs.Arg(0))}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintf(w, "Issue\tID\tAuthor\tStarted\tTime Spent\tComment\n")
+		for _, issue := range issues {
+			records, err := cmd.Worklogs(issue)
+			if err != nil {
+				w.Flush()
+				printError(err)
+				os.Exit(1)
+			}
+
+			for _, r := range records {
+				author := ""
+				if r.Author != nil {
+					author = r.Author.DisplayName
+				}
+
+				started := ""
+				if r.Started != nil {
+					started = time.Time(*r.Started).Local().Format("2006-01-02 15:04")
+				}
+
+				firstLine, _, _ := strings.Cut(r.Comment, "\n")
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", issue, r.ID, author, started, r.TimeSpent, firstLine)
+			}
+		}
+		w.Flush()
+	case "undo":
+		err := undo.Parse(os.Args[2:])
+		if err != nil || undo.NArg() > 1 {
+			fmt.Println("Usage: jiwa undo [operation-id]")
+			fmt.Println("jiwa undo --list")
+			os.Exit(1)
+		}
+
+		if *undoList {
+			ops, err := cmd.Operations()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+			fmt.Fprintf(w, "ID\tCommand\tTime\tUndone\tIssues\n")
+			for _, op := range ops {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", op.ID, op.Command, op.Time.Format(time.DateTime), op.Undone, strings.Join(op.Keys(), ","))
+			}
+			w.Flush()
+			return
+		}
+
+		op, restored, err := cmd.Undo(undo.Arg(0))
+		for _, key := range restored {
+			fmt.Println(cmd.ConstructIssueURL(key))
+		}
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "Undid %s %s\n", op.Command, op.ID)
+	case "unlink":
+		err := unlink.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("jiwa unlink <issue-id> <issue-id>")
+			fmt.Println("echo \"<issue-id>\" | jiwa unlink <issue-id>")
+			os.Exit(1)
+		}
+
+		var target string
+		var issues []string
+		if (stat.Mode() & os.ModeCharDevice) == 0 {
+			if len(unlink.Args()) != 1 {
+				fmt.Println("Usage: jiwa unlink <issue-id>")
+				os.Exit(1)
+			}
+
+			issues, err = cmd.ReadIssueListFromStdin()
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			target = cmd.StripBaseURL(unlink.Arg(0))
+		} else {
+			if len(unlink.Args()) != 2 {
+				fmt.Println("Usage: jiwa unlink <issue-id> <issue-id>")
+				os.Exit(1)
+			}
+
+			issues = []string{cmd.StripBaseURL(unlink.Arg(0))}
+			target = cmd.StripBaseURL(unlink.Arg(1))
+		}
+
+		unlinkedIssues, err := cmd.Unlink(issues, *unlinkType, target)
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		for _, issue := range unlinkedIssues {
 			fmt.Println(cmd.ConstructIssueURL(issue))
 		}
 	case "search":
@@ -556,14 +1705,65 @@ func main() {
 			os.Exit(1)
 		}
 
-		issues, err := cmd.Search(search.Arg(0))
+		issues, errs := cmd.SearchStream(search.Arg(0), jiwa.SearchOptions{
+			Limit:    *searchLimit,
+			PageSize: *searchPageSize,
+		})
+		for i := range issues {
+			fmt.Println(cmd.ConstructIssueURL(i.Key))
+		}
+
+		err = <-errs
 		if err != nil {
-			fmt.Println(err)
+			printError(err)
 			os.Exit(1)
 		}
+	}
+}
 
-		for _, i := range issues {
-			fmt.Println(cmd.ConstructIssueURL(i.Key))
+// printCreated prints the URL of a created issue, a dry run only has a
+// placeholder key that has no page in Jira.
+func printCreated(cmd *commands.Command, key string) {
+	if *dryRun {
+		fmt.Println("would create " + key)
+		return
+	}
+
+	fmt.Println(cmd.ConstructIssueURL(key))
+}
+
+// printError writes err to stderr, field errors coming back from Jira get a
+// line each so they are readable when creating or editing tickets.
+func printError(err error) {
+	var bulkErr *commands.BulkError
+	if errors.As(err, &bulkErr) {
+		for _, f := range bulkErr.Failed {
+			fmt.Fprintf(os.Stderr, "%s: ", f.Key)
+			printAPIError(f.Err)
+		}
+		if len(bulkErr.Skipped) != 0 {
+			fmt.Fprintf(os.Stderr, "skipped %s\n", strings.Join(bulkErr.Skipped, ", "))
 		}
+		fmt.Fprintln(os.Stderr, bulkErr)
+		return
+	}
+
+	printAPIError(err)
+}
+
+// printAPIError prints the messages Jira sent along with the error.
+func printAPIError(err error) {
+	var apiErr *jiwa.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s failed with %d:\n", apiErr.Method, apiErr.Endpoint, apiErr.StatusCode)
+	for _, msg := range apiErr.ErrorMessages {
+		fmt.Fprintf(os.Stderr, "  %s\n", msg)
+	}
+	for _, field := range apiErr.FieldNames() {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", field, apiErr.Errors[field])
 	}
 }
@@ -6,6 +6,9 @@ require (
 	github.com/andygrunwald/go-jira v1.16.0
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.8.4
+	github.com/trivago/tgo v1.0.7
+	golang.org/x/crypto v0.17.0
+	golang.org/x/term v0.15.0
 )
 
 require (
@@ -15,6 +18,6 @@ require (
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/trivago/tgo v1.0.7 // indirect
+	golang.org/x/sys v0.15.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
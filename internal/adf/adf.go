@@ -0,0 +1,34 @@
+// Package adf converts between plain text and the Atlassian Document Format
+// that version 3 of the Jira REST API uses for descriptions and comments.
+//
+// Writing understands the common bits of markdown people type into their
+// editor (headings, lists, code fences, quotes, tables, emphasis and
+// links), reading renders a document back into that same dialect so
+// round trips through `jiwa edit` don't lose formatting.
+package adf
+
+// Node is a single element of a document, both block nodes like paragraphs
+// and inline nodes like text use the same structure.
+type Node struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+}
+
+// Mark is formatting applied to a text node.
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Doc wraps the given blocks into a top level document node.
+func Doc(blocks ...Node) Node {
+	if blocks == nil {
+		blocks = []Node{}
+	}
+
+	return Node{Type: "doc", Version: 1, Content: blocks}
+}
@@ -0,0 +1,130 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromText(t *testing.T) {
+	testData := []struct {
+		Name    string
+		InText  string
+		OutJSON string
+	}{
+		{
+			Name:    "Paragraph",
+			InText:  "hello world",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"hello world"}]}]}`,
+		},
+		{
+			Name:    "LinesInParagraphKeepTheirBreaks",
+			InText:  "first\nsecond",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"first"},{"type":"hardBreak"},{"type":"text","text":"second"}]}]}`,
+		},
+		{
+			Name:    "Heading",
+			InText:  "## Steps",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]}]}`,
+		},
+		{
+			Name:    "CodeFence",
+			InText:  "```go\nfmt.Println()\n```",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"fmt.Println()"}]}]}`,
+		},
+		{
+			Name:    "InlineMarks",
+			InText:  "**bold** and `code` and [link](https://example.com)",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"bold","marks":[{"type":"strong"}]},{"type":"text","text":" and "},{"type":"text","text":"code","marks":[{"type":"code"}]},{"type":"text","text":" and "},{"type":"text","text":"link","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]}]}`,
+		},
+		{
+			Name:    "NestedBulletList",
+			InText:  "- one\n  - nested\n- two",
+			OutJSON: `{"type":"doc","version":1,"content":[{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one"}]},{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}]}]},{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}]}]}`,
+		},
+		{
+			Name:    "Empty",
+			InText:  "",
+			OutJSON: `{"type":"doc","version":1}`,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			result, err := json.Marshal(FromText(td.InText))
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, td.OutJSON, string(result))
+		})
+	}
+}
+
+func TestToText(t *testing.T) {
+	testData := []struct {
+		Name    string
+		InJSON  string
+		OutText string
+	}{
+		{
+			Name:    "MentionAndBreak",
+			InJSON:  `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"123","text":"@Philipp"}},{"type":"hardBreak"},{"type":"text","text":"please look"}]}]}`,
+			OutText: "@Philipp\nplease look",
+		},
+		{
+			Name:    "OrderedListWithStart",
+			InJSON:  `{"type":"doc","version":1,"content":[{"type":"orderedList","attrs":{"order":3},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]},{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"four"}]}]}]}]}`,
+			OutText: "3. three\n4. four",
+		},
+		{
+			Name:    "UnknownBlockFallsBackToContent",
+			InJSON:  `{"type":"doc","version":1,"content":[{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"careful"}]}]}]}`,
+			OutText: "careful",
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			var doc Node
+			err := json.Unmarshal([]byte(td.InJSON), &doc)
+
+			assert.NoError(t, err)
+			assert.Equal(t, td.OutText, ToText(doc))
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	testData := []struct {
+		Name   string
+		InText string
+	}{
+		{Name: "Paragraphs", InText: "first paragraph\n\nsecond paragraph\nwith a second line"},
+		{Name: "Heading", InText: "# Title\n\nbody"},
+		{Name: "Lists", InText: "- one\n  - nested\n- two\n\n1. first\n2. second"},
+		{Name: "Code", InText: "```sh\njiwa list | jiwa mv done\n```"},
+		{Name: "Quote", InText: "> quoted text"},
+		{Name: "Table", InText: "| a | b |\n| --- | --- |\n| 1 | 2 |"},
+		{Name: "Marks", InText: "**bold** *em* ~~gone~~ `code` [link](https://example.com) https://example.com"},
+		{Name: "Rule", InText: "above\n\n---\n\nbelow"},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			b, err := json.Marshal(FromText(td.InText))
+			assert.NoError(t, err)
+
+			var doc Node
+			err = json.Unmarshal(b, &doc)
+			assert.NoError(t, err)
+
+			assert.Equal(t, td.InText, ToText(doc))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package adf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToText renders a document back into readable text, using the same
+// markdown dialect FromText understands.
+func ToText(doc Node) string {
+	return strings.TrimRight(renderBlocks(doc.Content, "\n\n"), "\n")
+}
+
+func renderBlocks(blocks []Node, sep string) string {
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		parts = append(parts, renderBlock(b))
+	}
+
+	return strings.Join(parts, sep)
+}
+
+func renderBlock(n Node) string {
+	switch n.Type {
+	case "paragraph":
+		return renderInline(n.Content)
+	case "heading":
+		level := intAttr(n, "level", 1)
+		return strings.Repeat("#", level) + " " + renderInline(n.Content)
+	case "codeBlock":
+		language, _ := n.Attrs["language"].(string)
+		return "```" + language + "\n" + plainText(n.Content) + "\n```"
+	case "rule":
+		return "---"
+	case "blockquote":
+		return prefixLines(renderBlocks(n.Content, "\n\n"), "> ", "> ")
+	case "bulletList", "orderedList":
+		return renderList(n)
+	case "table":
+		return renderTable(n)
+	case "mediaSingle", "mediaGroup":
+		return renderBlocks(n.Content, "\n")
+	case "media":
+		if alt, ok := n.Attrs["alt"].(string); ok && alt != "" {
+			return "[attachment: " + alt + "]"
+		}
+		return "[attachment]"
+	case "blockCard", "embedCard":
+		u, _ := n.Attrs["url"].(string)
+		return u
+	default:
+		// panels, expands and whatever else gets added later, fall back to
+		// their content so no text is lost
+		if n.Text != "" {
+			return renderInline([]Node{n})
+		}
+		return renderBlocks(n.Content, "\n\n")
+	}
+}
+
+func renderList(list Node) string {
+	order := intAttr(list, "order", 1)
+
+	items := make([]string, 0, len(list.Content))
+	for i, item := range list.Content {
+		marker := "- "
+		if list.Type == "orderedList" {
+			marker = strconv.Itoa(order+i) + ". "
+		}
+
+		// nested lists are separated by a single newline only to keep the
+		// list in one piece
+		body := make([]string, 0, len(item.Content))
+		for _, child := range item.Content {
+			body = append(body, renderBlock(child))
+		}
+
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, prefixLines(strings.Join(body, "\n"), marker, indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func renderTable(table Node) string {
+	rows := make([]string, 0, len(table.Content))
+	for i, row := range table.Content {
+		cells := make([]string, 0, len(row.Content))
+		header := false
+		for _, cell := range row.Content {
+			header = header || cell.Type == "tableHeader"
+			text := strings.ReplaceAll(renderBlocks(cell.Content, " "), "\n", " ")
+			cells = append(cells, text)
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+
+		if i == 0 && header {
+			rows = append(rows, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func renderInline(nodes []Node) string {
+	b := strings.Builder{}
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(applyMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			text, _ := n.Attrs["text"].(string)
+			if !strings.HasPrefix(text, "@") {
+				text = "@" + text
+			}
+			b.WriteString(text)
+		case "emoji":
+			if text, ok := n.Attrs["text"].(string); ok && text != "" {
+				b.WriteString(text)
+				continue
+			}
+			shortName, _ := n.Attrs["shortName"].(string)
+			b.WriteString(shortName)
+		case "inlineCard":
+			u, _ := n.Attrs["url"].(string)
+			b.WriteString(u)
+		case "status":
+			text, _ := n.Attrs["text"].(string)
+			b.WriteString("[" + text + "]")
+		case "date":
+			b.WriteString(renderDate(n))
+		default:
+			b.WriteString(renderInline(n.Content))
+		}
+	}
+
+	return b.String()
+}
+
+func applyMarks(text string, marks []Mark) string {
+	var href string
+	for _, m := range marks {
+		switch m.Type {
+		case "code":
+			text = "`" + text + "`"
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ = m.Attrs["href"].(string)
+		}
+	}
+
+	if href != "" && href != text {
+		text = "[" + text + "](" + href + ")"
+	}
+
+	return text
+}
+
+// plainText concatenates text without applying any marks, used for code
+// blocks where marks aren't allowed anyway.
+func plainText(nodes []Node) string {
+	b := strings.Builder{}
+	for _, n := range nodes {
+		b.WriteString(n.Text)
+	}
+
+	return b.String()
+}
+
+func renderDate(n Node) string {
+	timestamp := fmt.Sprint(n.Attrs["timestamp"])
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return timestamp
+	}
+
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+// intAttr reads a numeric attribute, JSON numbers decode to float64 while
+// documents built by FromText hold ints.
+func intAttr(n Node, key string, fallback int) int {
+	switch v := n.Attrs[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return fallback
+	}
+}
+
+// prefixLines puts first in front of the first line and rest in front of
+// all the others.
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = first + lines[i]
+			continue
+		}
+		lines[i] = rest + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+package adf
+
+import (
+	"strings"
+
+	"github.com/catouc/jiwa/internal/markdown"
+)
+
+// FromText converts plain text or markdown into a document.
+func FromText(text string) Node {
+	return Doc(blocks(markdown.Parse(text))...)
+}
+
+// blocks renders the markdown blocks, empty lines only separate them.
+func blocks(bs []markdown.Block) []Node {
+	nodes := make([]Node, 0, len(bs))
+	for _, b := range bs {
+		if b.Kind == markdown.BlockBlank {
+			continue
+		}
+		nodes = append(nodes, block(b))
+	}
+
+	return nodes
+}
+
+func block(b markdown.Block) Node {
+	switch b.Kind {
+	case markdown.BlockHeading:
+		return Node{
+			Type:    "heading",
+			Attrs:   map[string]interface{}{"level": b.Level},
+			Content: inlineLines(b.Lines),
+		}
+	case markdown.BlockCode:
+		return codeBlock(b.Language, strings.Join(b.Code, "\n"))
+	case markdown.BlockRule:
+		return Node{Type: "rule"}
+	case markdown.BlockList:
+		return list(b)
+	case markdown.BlockQuote:
+		return Node{Type: "blockquote", Content: blocks(b.Children)}
+	case markdown.BlockTable:
+		return table(b)
+	default:
+		return Node{Type: "paragraph", Content: inlineLines(b.Lines)}
+	}
+}
+
+func codeBlock(language, code string) Node {
+	n := Node{Type: "codeBlock"}
+	if language != "" {
+		n.Attrs = map[string]interface{}{"language": language}
+	}
+	if code != "" {
+		n.Content = []Node{{Type: "text", Text: code}}
+	}
+
+	return n
+}
+
+func list(b markdown.Block) Node {
+	n := Node{Type: "bulletList"}
+	if b.Ordered {
+		n.Type = "orderedList"
+		if b.Start != 1 {
+			n.Attrs = map[string]interface{}{"order": b.Start}
+		}
+	}
+
+	for _, item := range b.Items {
+		li := Node{
+			Type:    "listItem",
+			Content: []Node{{Type: "paragraph", Content: inlineLines(item.Lines)}},
+		}
+		for _, nested := range item.Lists {
+			li.Content = append(li.Content, list(nested))
+		}
+		n.Content = append(n.Content, li)
+	}
+
+	return n
+}
+
+func table(b markdown.Block) Node {
+	n := Node{Type: "table"}
+
+	for i, row := range b.Rows {
+		cellType := "tableCell"
+		if i == 0 && b.Header {
+			cellType = "tableHeader"
+		}
+
+		tableRow := Node{Type: "tableRow"}
+		for _, cell := range row {
+			paragraph := Node{Type: "paragraph", Content: inline(cell, nil)}
+			tableRow.Content = append(tableRow.Content, Node{Type: cellType, Content: []Node{paragraph}})
+		}
+		n.Content = append(n.Content, tableRow)
+	}
+
+	return n
+}
+
+// inlineLines renders each line and joins them with hard breaks so the
+// line structure of the source survives.
+func inlineLines(lines [][]markdown.Inline) []Node {
+	nodes := make([]Node, 0)
+	for i, line := range lines {
+		if i > 0 {
+			nodes = append(nodes, Node{Type: "hardBreak"})
+		}
+		nodes = append(nodes, inline(line, nil)...)
+	}
+
+	return nodes
+}
+
+// inline flattens the inline tree into text nodes carrying the marks of
+// everything they are nested in.
+func inline(in []markdown.Inline, marks []Mark) []Node {
+	nodes := make([]Node, 0, len(in))
+
+	for _, i := range in {
+		switch i.Kind {
+		case markdown.InlineCode:
+			nodes = append(nodes, textNode(i.Text, withMark(marks, Mark{Type: "code"})))
+		case markdown.InlineStrong:
+			nodes = append(nodes, inline(i.Children, withMark(marks, Mark{Type: "strong"}))...)
+		case markdown.InlineEmphasis:
+			nodes = append(nodes, inline(i.Children, withMark(marks, Mark{Type: "em"}))...)
+		case markdown.InlineStrike:
+			nodes = append(nodes, inline(i.Children, withMark(marks, Mark{Type: "strike"}))...)
+		case markdown.InlineLink:
+			nodes = append(nodes, inline(i.Children, withMark(marks, linkMark(i.URL)))...)
+		case markdown.InlineImage:
+			// images need to be uploaded as media first, a link to them
+			// keeps the reference
+			text := i.Text
+			if text == "" {
+				text = i.URL
+			}
+			nodes = append(nodes, textNode(text, withMark(marks, linkMark(i.URL))))
+		case markdown.InlineAutoLink:
+			nodes = append(nodes, textNode(i.URL, withMark(marks, linkMark(i.URL))))
+		default:
+			nodes = append(nodes, textNode(i.Text, marks))
+		}
+	}
+
+	return nodes
+}
+
+func textNode(text string, marks []Mark) Node {
+	return Node{Type: "text", Text: text, Marks: marks}
+}
+
+func linkMark(href string) Mark {
+	return Mark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+}
+
+// withMark returns a copy of marks with m added so sibling nodes don't
+// share the same backing array.
+func withMark(marks []Mark, m Mark) []Mark {
+	result := make([]Mark, 0, len(marks)+1)
+	result = append(result, marks...)
+	return append(result, m)
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Attach uploads all files to every issue.
+func (c *Command) Attach(issues, files []string) ([]string, error) {
+	for _, issue := range issues {
+		for _, file := range files {
+			err := c.attachFile(issue, file)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func (c *Command) attachFile(issue, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	_, err = c.Client.AddAttachment(context.TODO(), issue, filepath.Base(file), f)
+	return err
+}
+
+// AttachReader uploads everything read from r as a single attachment
+// called name, used to attach stdin.
+func (c *Command) AttachReader(issue, name string, r io.Reader) error {
+	if name == "" {
+		return fmt.Errorf("a name is needed to attach data from stdin")
+	}
+
+	_, err := c.Client.AddAttachment(context.TODO(), issue, name, r)
+	return err
+}
+
+func (c *Command) Attachments(issue string) ([]jira.Attachment, error) {
+	attachments, err := c.Client.ListAttachments(context.TODO(), issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// DownloadAttachment finds the attachment on the issue either by its ID or
+// its file name and opens its content. If several attachments share a
+// name the most recent one wins.
+func (c *Command) DownloadAttachment(issue, idOrName string) (jira.Attachment, io.ReadCloser, error) {
+	attachments, err := c.Client.ListAttachments(context.TODO(), issue)
+	if err != nil {
+		return jira.Attachment{}, nil, err
+	}
+
+	var found *jira.Attachment
+	for i, a := range attachments {
+		if a.ID == idOrName {
+			found = &attachments[i]
+			break
+		}
+
+		if a.Filename == idOrName && (found == nil || a.Created > found.Created) {
+			found = &attachments[i]
+		}
+	}
+
+	if found == nil {
+		return jira.Attachment{}, nil, fmt.Errorf("no attachment %s on %s", idOrName, issue)
+	}
+
+	content, err := c.Client.DownloadAttachment(context.TODO(), *found)
+	if err != nil {
+		return jira.Attachment{}, nil, err
+	}
+
+	return *found, content, nil
+}
@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/catouc/jiwa/internal/jiwa"
+)
+
+// Values of Config.Auth.
+const (
+	AuthBasic   = "basic"
+	AuthPAT     = "pat"
+	AuthSession = "session"
+	AuthOAuth2  = "oauth2"
+)
+
+// DefaultOAuth2RedirectURL is where jiwa waits for the OAuth 2.0
+// authorization code, it has to be registered as callback URL of the app.
+const DefaultOAuth2RedirectURL = "http://localhost:8976/callback"
+
+// OAuth2Config configures the OAuth 2.0 (3LO) app jiwa authenticates as.
+type OAuth2Config struct {
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectURL"`
+	Scopes       []string `json:"scopes"`
+	// CloudID of the site, jiwa looks it up on login if it's not set.
+	CloudID string `json:"cloudId"`
+	// TokenFile is where the access and refresh token are kept.
+	TokenFile string `json:"tokenFile"`
+}
+
+// Authenticator builds the jiwa.Authenticator selected by Auth.
+func (c *Config) Authenticator(httpClient *http.Client) (jiwa.Authenticator, error) {
+	switch c.Auth {
+	case "":
+		if c.Password != "" {
+			return &jiwa.BasicAuth{Username: c.Username, Password: c.Password}, nil
+		}
+		return &jiwa.PersonalAccessToken{Token: c.Token}, nil
+	case AuthBasic:
+		return &jiwa.BasicAuth{Username: c.Username, Password: c.Password}, nil
+	case AuthPAT:
+		return &jiwa.PersonalAccessToken{Token: c.Token}, nil
+	case AuthSession:
+		return &jiwa.SessionAuth{
+			BaseURL:    c.siteURL(),
+			Username:   c.Username,
+			Password:   c.Password,
+			HTTPClient: httpClient,
+			CookieFile: filepath.Join(filepath.Dir(c.OAuth2.TokenFile), "session.json"),
+		}, nil
+	case AuthOAuth2:
+		redirectURL := c.OAuth2.RedirectURL
+		if redirectURL == "" {
+			redirectURL = DefaultOAuth2RedirectURL
+		}
+
+		return &jiwa.OAuth2{
+			ClientID:     c.OAuth2.ClientID,
+			ClientSecret: c.OAuth2.ClientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       c.OAuth2.Scopes,
+			Store:        tokenStore{path: c.OAuth2.TokenFile, passphrase: c.Passphrase},
+			HTTPClient:   httpClient,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth %q, use one of %s, %s, %s or %s", c.Auth, AuthBasic, AuthPAT, AuthSession, AuthOAuth2)
+	}
+}
+
+// siteURL is the URL of the Jira site including the endpoint prefix.
+func (c *Config) siteURL() string {
+	return c.BaseURL + "/" + c.ReturnCleanEndpointPrefix()
+}
+
+// APIBaseURL is the URL the client sends its requests to, with OAuth 2.0
+// that's Atlassian's API gateway instead of the site itself.
+func (c *Config) APIBaseURL() (string, error) {
+	if c.Auth != AuthOAuth2 {
+		return c.siteURL(), nil
+	}
+
+	cloudID := c.OAuth2.CloudID
+	if cloudID == "" {
+		token, err := tokenStore{path: c.OAuth2.TokenFile, passphrase: c.Passphrase}.Load()
+		if err != nil {
+			return "", fmt.Errorf("no OAuth 2.0 token, run `jiwa login` first: %w", err)
+		}
+		cloudID = token.CloudID
+	}
+
+	if cloudID == "" {
+		return "", errors.New("the cloud ID of the site is unknown, set oauth2.cloudId or run `jiwa login` again")
+	}
+
+	return jiwa.OAuth2APIURL + cloudID, nil
+}
+
+// Login logs in with the configured session or OAuth 2.0 credentials, for
+// OAuth 2.0 this walks the user through granting jiwa access in a browser.
+func (c *Command) Login() error {
+	switch auth := c.Client.Auth.(type) {
+	case *jiwa.OAuth2:
+		return c.loginOAuth2(auth)
+	case jiwa.Refresher:
+		return auth.Refresh(context.TODO())
+	default:
+		return errors.New("logging in is only needed with the session or oauth2 auth")
+	}
+}
+
+func (c *Command) loginOAuth2(auth *jiwa.OAuth2) error {
+	redirect, err := url.Parse(auth.RedirectURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse redirect URL: %w", err)
+	}
+
+	stateBytes := make([]byte, 16)
+	_, err = rand.Read(stateBytes)
+	if err != nil {
+		return fmt.Errorf("failed to generate state: %w", err)
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the redirect on %s: %w", redirect.Host, err)
+	}
+
+	// only the first redirect counts, the channels are never read twice
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+		case query.Get("error") != "":
+			http.Error(w, query.Get("error_description"), http.StatusBadRequest)
+			select {
+			case errs <- fmt.Errorf("access was not granted: %s", query.Get("error_description")):
+			default:
+			}
+		default:
+			fmt.Fprintln(w, "jiwa is logged in, you can close this window.")
+			select {
+			case codes <- query.Get("code"):
+			default:
+			}
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	fmt.Fprintf(os.Stderr, "Open this URL in your browser to grant jiwa access:\n\n%s\n\n", auth.AuthCodeURL(state))
+
+	var code string
+	select {
+	case code = <-codes:
+	case err = <-errs:
+		return err
+	case <-time.After(5 * time.Minute):
+		return errors.New("timed out waiting for the redirect")
+	}
+
+	_, err = auth.Exchange(context.TODO(), code)
+	if err != nil {
+		return err
+	}
+
+	cloudID := c.Config.OAuth2.CloudID
+	if cloudID == "" {
+		cloudID, err = c.findCloudID(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = auth.SetCloudID(cloudID)
+	if err != nil {
+		return err
+	}
+
+	// responses are only cached once the token is known to belong to
+	// this user
+	return auth.LookUpAccount(context.TODO())
+}
+
+// findCloudID picks the site matching the configured base URL out of the
+// sites the token grants access to.
+func (c *Command) findCloudID(auth *jiwa.OAuth2) (string, error) {
+	resources, err := auth.AccessibleResources(context.TODO())
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range resources {
+		if strings.TrimSuffix(r.URL, "/") == strings.TrimSuffix(c.Config.BaseURL, "/") {
+			return r.ID, nil
+		}
+	}
+
+	if len(resources) == 1 {
+		return resources[0].ID, nil
+	}
+
+	return "", fmt.Errorf("the token does not grant access to %s, set oauth2.cloudId to pick a site", c.Config.BaseURL)
+}
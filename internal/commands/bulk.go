@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BulkOptions controls how commands that work on many issues at once run.
+type BulkOptions struct {
+	// Parallel is the amount of issues worked on at the same time, values
+	// below 1 work on one issue at a time.
+	Parallel int
+	// ContinueOnError keeps working on the remaining issues after one
+	// failed, otherwise they are skipped.
+	ContinueOnError bool
+}
+
+// IssueFailure is an issue a bulk operation failed on.
+type IssueFailure struct {
+	Key string
+	Err error
+}
+
+// BulkError is returned by bulk operations that failed on some issues.
+type BulkError struct {
+	Total  int
+	Failed []IssueFailure
+	// Skipped are the issues that weren't attempted because an earlier
+	// one failed.
+	Skipped []string
+}
+
+func (e *BulkError) Error() string {
+	msg := fmt.Sprintf("%d of %d issues failed", len(e.Failed), e.Total)
+	if len(e.Skipped) != 0 {
+		msg += fmt.Sprintf(", %d skipped", len(e.Skipped))
+	}
+
+	return msg
+}
+
+// Unwrap makes errors.Is and errors.As look at the individual failures.
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f.Err)
+	}
+
+	return errs
+}
+
+// bulk runs op on every issue, at most c.Bulk.Parallel at a time. It returns the
+// issues op succeeded on in their original order, and a *BulkError if it
+// failed on any.
+func (c *Command) bulk(issues []string, op func(ctx context.Context, issue string) error) ([]string, error) {
+	workers := c.Bulk.Parallel
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx := context.TODO()
+	errs := make([]error, len(issues))
+	attempted := make([]bool, len(issues))
+
+	var mu sync.Mutex
+	failed := false
+
+	// a slot is only freed once the result of the issue is recorded, so
+	// no further issue is started after a failure
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range issues {
+		slots <- struct{}{}
+
+		mu.Lock()
+		stop := failed && !c.Bulk.ContinueOnError
+		mu.Unlock()
+		if stop {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			err := op(ctx, issues[i])
+
+			mu.Lock()
+			errs[i] = err
+			attempted[i] = true
+			if err != nil {
+				failed = true
+			}
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := make([]string, 0, len(issues))
+	bulkErr := &BulkError{Total: len(issues)}
+	for i, issue := range issues {
+		switch {
+		case !attempted[i]:
+			bulkErr.Skipped = append(bulkErr.Skipped, issue)
+		case errs[i] != nil:
+			bulkErr.Failed = append(bulkErr.Failed, IssueFailure{Key: issue, Err: errs[i]})
+		default:
+			succeeded = append(succeeded, issue)
+		}
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		return succeeded, bulkErr
+	}
+
+	return succeeded, nil
+}
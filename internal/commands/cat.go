@@ -7,6 +7,15 @@ import (
 )
 
 func (c *Command) Cat(issueID string) (jira.Issue, error) {
+	if c.Offline {
+		store, err := c.mirror()
+		if err != nil {
+			return jira.Issue{}, err
+		}
+
+		return store.Get(issueID)
+	}
+
 	issue, err := c.Client.GetIssue(context.TODO(), issueID)
 	if err != nil {
 		return jira.Issue{}, err
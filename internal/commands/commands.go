@@ -12,36 +12,88 @@ import (
 	"strings"
 	"time"
 
+	"github.com/andygrunwald/go-jira"
 	"github.com/catouc/jiwa/internal/editor"
 	"github.com/catouc/jiwa/internal/jiwa"
+	"github.com/catouc/jiwa/internal/markup"
 )
 
 type Command struct {
 	Config Config
-	Client jiwa.Client
+	Client *jiwa.Client
+	// Offline makes the commands that can answer from the mirror created
+	// by Sync and fails the others with ErrOffline.
+	Offline bool
+	// Bulk controls how Move, Label, Comment and Reassign work through
+	// their issues.
+	Bulk BulkOptions
 }
 
 type Config struct {
-	BaseURL        string        `json:"baseURL"`
-	APIVersion     string        `json:"apiVersion"`
-	EndpointPrefix string        `json:"endpointPrefix"`
-	Username       string        `json:"username"`
-	Password       string        `json:"password"`
-	Token          string        `json:"token"`
-	Timeout        time.Duration `json:"timeout"`
-	DefaultProject string        `json:"defaultProject"`
+	BaseURL string `json:"baseURL"`
+	// APIVersion of the core REST API, if empty 3 is used on Jira Cloud
+	// and 2 on Server and Data Center.
+	APIVersion string `json:"apiVersion"`
+	// DeploymentType is "Cloud", "Server" or "DataCenter" and saves jiwa
+	// asking the instance for it.
+	DeploymentType string `json:"deploymentType"`
+	EndpointPrefix string `json:"endpointPrefix"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	Token          string `json:"token"`
+	// Auth picks how jiwa authenticates, one of "basic", "pat", "session"
+	// or "oauth2". If empty basic auth is used when a password is set and
+	// the token is sent as a bearer token otherwise.
+	Auth   string       `json:"auth"`
+	OAuth2 OAuth2Config `json:"oauth2"`
+	// CredentialHelper is asked for the password or token when they
+	// aren't configured, "netrc" reads them from ~/.netrc and anything
+	// else is run like a git credential helper.
+	CredentialHelper string        `json:"credentialHelper"`
+	Timeout          time.Duration `json:"timeout"`
+	DefaultProject   string        `json:"defaultProject"`
+	// Markdown makes jiwa treat descriptions and comments as markdown,
+	// converting them to Jira's wiki markup and back.
+	Markdown bool `json:"markdown"`
+	// Retry configures how often failed calls against Jira are retried,
+	// see jiwa.RetryPolicy for the individual values.
+	Retry jiwa.RetryPolicy `json:"retry"`
+	// Cache keeps Jira's responses on disk between runs, see jiwa.Cache.
+	Cache CacheConfig `json:"cache"`
+	// MirrorDir is where Sync mirrors projects to, it defaults to
+	// $XDG_DATA_HOME/jiwa/mirror.
+	MirrorDir string `json:"mirrorDir"`
+	// UndoDir is where the operations that can be undone are recorded, it
+	// defaults to $XDG_DATA_HOME/jiwa/undo.
+	UndoDir string `json:"undoDir"`
+	// Passphrase the secrets were decrypted with, it's never written to
+	// the config file.
+	Passphrase []byte `json:"-"`
+}
+
+// CacheConfig turns the on-disk cache on, the remaining fields are the
+// ones of jiwa.Cache.
+type CacheConfig struct {
+	Enabled bool `json:"enabled"`
+	jiwa.Cache
 }
 
 func (c *Config) IsValid() bool {
-	switch {
-	case c.BaseURL == "":
-		return false
-	case c.Username == "":
-		return false
-	case c.Token == "" && c.Password == "":
+	if c.BaseURL == "" {
 		return false
+	}
+
+	switch c.Auth {
+	case "":
+		return c.Username != "" && (c.Token != "" || c.Password != "")
+	case AuthBasic, AuthSession:
+		return c.Username != "" && c.Password != ""
+	case AuthPAT:
+		return c.Token != ""
+	case AuthOAuth2:
+		return c.OAuth2.ClientID != "" && c.OAuth2.ClientSecret != ""
 	default:
-		return true
+		return false
 	}
 }
 
@@ -101,13 +153,44 @@ func BuildSummaryAndDescriptionFromScanner(scanner *bufio.Scanner) (string, stri
 	return title, descriptionBuilder.String(), scanner.Err()
 }
 
-func GetIssueIntoEditor(c jiwa.Client, key string) (string, string, error) {
-	issue, err := c.GetIssue(context.TODO(), key)
+// GetIssueIntoEditor opens the summary and description of the issue in an
+// editor and returns the edited versions.
+func (c *Command) GetIssueIntoEditor(key string) (string, string, error) {
+	issue, err := c.Client.GetIssue(context.TODO(), key)
+	if err != nil {
+		return "", "", err
+	}
+
+	return c.issueIntoEditor(issue)
+}
+
+func (c *Command) issueIntoEditor(issue jira.Issue) (string, string, error) {
+	summary, description, err := CreateIssueSummaryDescription(issue.Fields.Summary + "\n" + c.FromMarkup(issue.Fields.Description))
 	if err != nil {
 		return "", "", err
 	}
 
-	return CreateIssueSummaryDescription(issue.Fields.Summary + "\n" + issue.Fields.Description)
+	return summary, c.ToMarkup(description), nil
+}
+
+// ToMarkup converts text the user wrote in markdown to Jira's wiki markup.
+// With API version 3 the client takes care of converting to ADF so the
+// text is passed as is.
+func (c *Command) ToMarkup(text string) string {
+	if !c.Config.Markdown || c.Client.UsesADF(context.TODO()) {
+		return text
+	}
+
+	return markup.FromMarkdown(text)
+}
+
+// FromMarkup is the inverse of ToMarkup, used to prefill the editor.
+func (c *Command) FromMarkup(text string) string {
+	if !c.Config.Markdown || c.Client.UsesADF(context.TODO()) {
+		return text
+	}
+
+	return markup.ToMarkdown(text)
 }
 
 func ReadStdin() ([]byte, error) {
@@ -1,8 +1,21 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
+	"github.com/catouc/jiwa/internal/oplog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -177,3 +190,660 @@ func TestCommand_StripBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestFindLinkType(t *testing.T) {
+	linkTypes := []jira.IssueLinkType{
+		{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+		{Name: "Duplicate", Inward: "is duplicated by", Outward: "duplicates"},
+	}
+
+	testData := []struct {
+		Name       string
+		InName     string
+		OutName    string
+		OutInward  bool
+		OutErrored bool
+	}{
+		{Name: "ByName", InName: "blocks", OutName: "Blocks"},
+		{Name: "ByOutward", InName: "Duplicates", OutName: "Duplicate"},
+		{Name: "ByInward", InName: "is blocked by", OutName: "Blocks", OutInward: true},
+		{Name: "Unknown", InName: "relates to", OutErrored: true},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			lt, inward, err := findLinkType(linkTypes, td.InName)
+
+			assert.Equal(t, td.OutErrored, err != nil)
+			assert.Equal(t, td.OutName, lt.Name)
+			assert.Equal(t, td.OutInward, inward)
+		})
+	}
+}
+
+func TestCommand_Link(t *testing.T) {
+	testData := []struct {
+		Name        string
+		InLinkType  string
+		OutRequests []string
+		OutErrored  bool
+	}{
+		{
+			Name:        "Outward",
+			InLinkType:  "blocks",
+			OutRequests: []string{`{"type":{"name":"Blocks"},"inwardIssue":{"key":"JIWA-1"},"outwardIssue":{"key":"JIWA-9"}}`},
+		},
+		{
+			Name:        "InwardFlipsTheIssues",
+			InLinkType:  "is blocked by",
+			OutRequests: []string{`{"type":{"name":"Blocks"},"inwardIssue":{"key":"JIWA-9"},"outwardIssue":{"key":"JIWA-1"}}`},
+		},
+		{
+			Name:       "UnknownLinkType",
+			InLinkType: "relates to",
+			OutErrored: true,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issueLinkType" {
+					w.Write([]byte(`{"issueLinkTypes":[{"name":"Blocks","inward":"is blocked by","outward":"blocks"}]}`))
+					return
+				}
+
+				assert.Equal(t, "/rest/api/2/issueLink", r.URL.Path)
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, string(body))
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(server.Close)
+
+			c := Command{Client: &jiwa.Client{
+				Username:       "user",
+				Password:       "pass",
+				BaseURL:        server.URL,
+				APIVersion:     "2",
+				DeploymentType: jiwa.DeploymentServer,
+				HTTPClient:     server.Client(),
+			}}
+
+			_, err := c.Link([]string{"JIWA-1"}, td.InLinkType, "JIWA-9")
+			if td.OutErrored {
+				assert.ErrorContains(t, err, `valid link types are: "blocks"/"is blocked by"`)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, len(td.OutRequests), len(requests))
+			for i := range requests {
+				assert.JSONEq(t, td.OutRequests[i], requests[i])
+			}
+		})
+	}
+}
+
+func TestCommand_Watch(t *testing.T) {
+	testData := []struct {
+		Name             string
+		InDeploymentType string
+		OutRequests      []string
+	}{
+		{
+			Name:             "Server",
+			InDeploymentType: jiwa.DeploymentServer,
+			OutRequests: []string{
+				`POST issue/JIWA-1/watchers "alice"`,
+				`DELETE issue/JIWA-1/watchers?username=alice `,
+			},
+		},
+		{
+			Name:             "Cloud",
+			InDeploymentType: jiwa.DeploymentCloud,
+			OutRequests: []string{
+				`POST issue/JIWA-1/watchers "5b10ac8d82e05b22cc7d4ef5"`,
+				`DELETE issue/JIWA-1/watchers?accountId=5b10ac8d82e05b22cc7d4ef5 `,
+			},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/rest/api/2/myself" {
+					w.Write([]byte(`{"name":"alice","accountId":"5b10ac8d82e05b22cc7d4ef5"}`))
+					return
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				request := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/rest/api/2/")
+				if r.URL.RawQuery != "" {
+					request += "?" + r.URL.RawQuery
+				}
+				requests = append(requests, request+" "+string(body))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(server.Close)
+
+			c := Command{Client: &jiwa.Client{
+				Username:       "user",
+				Password:       "pass",
+				BaseURL:        server.URL,
+				APIVersion:     "2",
+				DeploymentType: td.InDeploymentType,
+				HTTPClient:     server.Client(),
+			}}
+
+			_, err := c.Watch([]string{"JIWA-1"}, "")
+			assert.NoError(t, err)
+			_, err = c.Unwatch([]string{"JIWA-1"}, "")
+			assert.NoError(t, err)
+			assert.Equal(t, td.OutRequests, requests)
+		})
+	}
+}
+
+func TestConfig_ReportCredentials(t *testing.T) {
+	testData := []struct {
+		Name          string
+		InStatus      int
+		OutHelperCall string
+	}{
+		{Name: "Accepted", InStatus: http.StatusOK, OutHelperCall: "store\n"},
+		{Name: "Rejected", InStatus: http.StatusUnauthorized, OutHelperCall: "erase\n"},
+		{Name: "ServerErrorKeepsThem", InStatus: http.StatusInternalServerError},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(td.InStatus)
+				w.Write([]byte(`{"key":"JIWA-1"}`))
+			}))
+			t.Cleanup(server.Close)
+
+			calls := filepath.Join(t.TempDir(), "calls")
+			cfg := Config{
+				BaseURL:          server.URL,
+				Username:         "user",
+				Password:         "pass",
+				CredentialHelper: `!f() { echo "$1" >> ` + calls + `; cat > /dev/null; }; f`,
+			}
+			client := &jiwa.Client{
+				Username:       "user",
+				Password:       "pass",
+				BaseURL:        server.URL,
+				APIVersion:     "2",
+				DeploymentType: jiwa.DeploymentServer,
+				HTTPClient:     server.Client(),
+				Authorized: func(ok bool) {
+					assert.NoError(t, cfg.ReportCredentials(context.Background(), ok))
+				},
+			}
+
+			// only the first answer reaches the helper
+			client.GetIssue(context.Background(), "JIWA-1")
+			client.GetIssue(context.Background(), "JIWA-1")
+
+			b, _ := os.ReadFile(calls)
+			assert.Equal(t, td.OutHelperCall, string(b))
+		})
+	}
+}
+
+func TestValidateCreateFields(t *testing.T) {
+	meta := []jiwa.FieldMeta{
+		{FieldID: "summary", Name: "Summary", Required: true},
+		{FieldID: "reporter", Name: "Reporter", Required: true, HasDefaultValue: true},
+		{FieldID: "components", Name: "Component/s", Required: true, AllowedValues: []jiwa.AllowedValue{{ID: "10", Name: "Backend"}}},
+		{FieldID: "customfield_10050", Name: "Team", AllowedValues: []jiwa.AllowedValue{{ID: "20", Value: "Platform"}}},
+	}
+
+	testData := []struct {
+		Name       string
+		InFields   map[string]interface{}
+		OutMissing []string
+		OutInvalid []string
+	}{
+		{
+			Name: "Valid",
+			InFields: map[string]interface{}{
+				"summary":           "Fix it",
+				"components":        []interface{}{map[string]string{"name": "backend"}},
+				"customfield_10050": map[string]string{"value": "Platform"},
+			},
+		},
+		{
+			Name:       "MissingRequired",
+			InFields:   map[string]interface{}{},
+			OutMissing: []string{"Component/s", "Summary"},
+		},
+		{
+			Name: "NotAllowed",
+			InFields: map[string]interface{}{
+				"summary":           "Fix it",
+				"components":        []interface{}{map[string]string{"name": "Frontend"}},
+				"customfield_10050": map[string]string{"value": "Infra"},
+			},
+			OutInvalid: []string{"Frontend", "Infra"},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateCreateFields(meta, td.InFields)
+			if td.OutMissing == nil && td.OutInvalid == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			var missing, invalid []string
+			for _, m := range err.Missing {
+				missing = append(missing, m.Name)
+			}
+			for _, i := range err.Invalid {
+				invalid = append(invalid, i.Value)
+			}
+			assert.Equal(t, td.OutMissing, missing)
+			assert.Equal(t, td.OutInvalid, invalid)
+		})
+	}
+}
+
+func TestSplitFieldBlock(t *testing.T) {
+	template := "Summary\n\nSome description\n" + fieldTemplate([]jiwa.FieldMeta{
+		{FieldID: "components", Name: "Component/s", AllowedValues: []jiwa.AllowedValue{{Name: "Backend"}}},
+		{FieldID: "priority", Name: "Priority"},
+	})
+	filled := strings.Replace(template, "Component/s=", "Component/s=Backend", 1)
+
+	description, assignments := splitFieldBlock(filled)
+	assert.Equal(t, "Summary\n\nSome description\n", description)
+	assert.Equal(t, []string{"Component/s=Backend"}, assignments)
+}
+
+func TestConfig_EncryptDecryptConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	secretsPath := filepath.Join(dir, "secrets.enc")
+	original := `{"baseURL":"https://jira.example.com","username":"bob","password":"hunter2","oauth2":{"clientId":"id","clientSecret":"s3cret"}}`
+	err := os.WriteFile(cfgPath, []byte(original), 0o600)
+	assert.NoError(t, err)
+
+	cfg := Config{OAuth2: OAuth2Config{TokenFile: filepath.Join(dir, "token.json")}}
+	passphrase := []byte("correct horse")
+
+	err = cfg.EncryptConfig(cfgPath, secretsPath, passphrase)
+	assert.NoError(t, err)
+
+	encrypted, err := os.ReadFile(cfgPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "hunter2")
+	assert.NotContains(t, string(encrypted), "s3cret")
+	assert.Contains(t, string(encrypted), `"clientId": "id"`)
+
+	var loaded Config
+	err = loaded.LoadSecrets(secretsPath, passphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", loaded.Password)
+	assert.Equal(t, "s3cret", loaded.OAuth2.ClientSecret)
+
+	err = cfg.DecryptConfig(cfgPath, secretsPath, passphrase)
+	assert.NoError(t, err)
+
+	decrypted, err := os.ReadFile(cfgPath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, original, string(decrypted))
+	assert.NoFileExists(t, secretsPath)
+}
+
+func TestConfig_EncryptConfigAgain(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	secretsPath := filepath.Join(dir, "secrets.enc")
+	err := os.WriteFile(cfgPath, []byte(`{"baseURL":"https://jira.example.com","password":"hunter2"}`), 0o600)
+	assert.NoError(t, err)
+
+	passphrase := []byte("correct horse")
+	cfg := Config{OAuth2: OAuth2Config{TokenFile: filepath.Join(dir, "token.json")}}
+	err = tokenStore{path: cfg.OAuth2.TokenFile}.Save(jiwa.OAuth2Token{AccessToken: "access"})
+	assert.NoError(t, err)
+
+	err = cfg.EncryptConfig(cfgPath, secretsPath, passphrase)
+	assert.NoError(t, err)
+
+	// the next run has the passphrase and the token is sealed
+	cfg.Passphrase = passphrase
+	err = os.WriteFile(cfgPath, []byte(`{"baseURL":"https://jira.example.com","password":"changed"}`), 0o600)
+	assert.NoError(t, err)
+
+	err = cfg.EncryptConfig(cfgPath, secretsPath, passphrase)
+	assert.NoError(t, err)
+
+	var loaded Config
+	err = loaded.LoadSecrets(secretsPath, passphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", loaded.Password)
+
+	token, err := tokenStore{path: cfg.OAuth2.TokenFile, passphrase: passphrase}.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+
+	// without the passphrase the token can't be read, nothing is touched
+	cfg.Passphrase = nil
+	err = os.WriteFile(cfgPath, []byte(`{"baseURL":"https://jira.example.com","password":"third"}`), 0o600)
+	assert.NoError(t, err)
+
+	err = cfg.EncryptConfig(cfgPath, secretsPath, passphrase)
+	assert.Error(t, err)
+
+	b, err := os.ReadFile(cfgPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "third")
+	err = loaded.LoadSecrets(secretsPath, passphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, "changed", loaded.Password)
+}
+
+func TestCommand_bulk(t *testing.T) {
+	testData := []struct {
+		Name         string
+		InOptions    BulkOptions
+		InIssues     []string
+		OutSucceeded []string
+		OutFailed    []string
+		OutSkipped   []string
+	}{
+		{
+			Name:         "AllSucceed",
+			InOptions:    BulkOptions{Parallel: 4},
+			InIssues:     []string{"JIWA-1", "JIWA-2", "JIWA-3"},
+			OutSucceeded: []string{"JIWA-1", "JIWA-2", "JIWA-3"},
+		},
+		{
+			Name:         "StopOnError",
+			InIssues:     []string{"JIWA-1", "FAIL-2", "JIWA-3"},
+			OutSucceeded: []string{"JIWA-1"},
+			OutFailed:    []string{"FAIL-2"},
+			OutSkipped:   []string{"JIWA-3"},
+		},
+		{
+			Name:         "ContinueOnError",
+			InOptions:    BulkOptions{Parallel: 2, ContinueOnError: true},
+			InIssues:     []string{"FAIL-1", "JIWA-2", "FAIL-3", "JIWA-4"},
+			OutSucceeded: []string{"JIWA-2", "JIWA-4"},
+			OutFailed:    []string{"FAIL-1", "FAIL-3"},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			c := Command{Bulk: td.InOptions}
+			succeeded, err := c.bulk(td.InIssues, func(_ context.Context, issue string) error {
+				if strings.HasPrefix(issue, "FAIL") {
+					return errors.New("failed")
+				}
+				return nil
+			})
+			assert.Equal(t, td.OutSucceeded, succeeded)
+
+			if len(td.OutFailed) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var bulkErr *BulkError
+			assert.ErrorAs(t, err, &bulkErr)
+
+			failed := make([]string, 0)
+			for _, f := range bulkErr.Failed {
+				failed = append(failed, f.Key)
+			}
+			assert.Equal(t, td.OutFailed, failed)
+			assert.Equal(t, td.OutSkipped, bulkErr.Skipped)
+			assert.Equal(t, len(td.InIssues), bulkErr.Total)
+		})
+	}
+}
+
+func TestSplitTicketDocuments(t *testing.T) {
+	testData := []struct {
+		Name string
+		In   string
+		Out  []string
+	}{
+		{
+			Name: "SingleTicket",
+			In:   "Summary\n\nDescription\n",
+			Out:  []string{"Summary\n\nDescription\n"},
+		},
+		{
+			Name: "MultipleTickets",
+			In:   "First\n\nBody\n---\nSecond\n--- \n\nThird\nPriority=High\n",
+			Out:  []string{"First\n\nBody\n", "Second\n", "Third\nPriority=High\n"},
+		},
+		{
+			Name: "EmptyDocuments",
+			In:   "---\nFirst\n---\n\n---\n",
+			Out:  []string{"First\n"},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.Out, splitTicketDocuments(td.In))
+		})
+	}
+}
+
+func TestCommand_Undo(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/rest/api/2/")+" "+string(body))
+		mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/JIWA-1":
+			w.Write([]byte(`{"key":"JIWA-1","fields":{"labels":["old"],"assignee":{"name":"alice"}}}`))
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"id":"10001"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := Command{
+		Config: Config{BaseURL: server.URL, UndoDir: t.TempDir()},
+		Client: &jiwa.Client{
+			Username:       "user",
+			Password:       "pass",
+			BaseURL:        server.URL,
+			APIVersion:     "2",
+			DeploymentType: jiwa.DeploymentServer,
+			HTTPClient:     server.Client(),
+		},
+	}
+
+	_, err := c.Label([]string{"JIWA-1"}, []string{"new"})
+	assert.NoError(t, err)
+	_, err = c.Comment([]string{"JIWA-1"}, "oops")
+	assert.NoError(t, err)
+
+	testData := []struct {
+		Name        string
+		OutCommand  string
+		OutRequests []string
+	}{
+		{
+			Name:        "Comment",
+			OutCommand:  "comment",
+			OutRequests: []string{"DELETE issue/JIWA-1/comment/10001 "},
+		},
+		{
+			Name:        "Label",
+			OutCommand:  "label",
+			OutRequests: []string{`PUT issue/JIWA-1 {"fields":{"labels":["old"]}}`},
+		},
+	}
+
+	// the latest operation is undone first
+	for _, td := range testData {
+		requests = nil
+
+		op, restored, err := c.Undo("")
+		assert.NoError(t, err, td.Name)
+		assert.Equal(t, td.OutCommand, op.Command, td.Name)
+		assert.Equal(t, []string{"JIWA-1"}, restored, td.Name)
+		assert.Equal(t, td.OutRequests, requests, td.Name)
+	}
+
+	_, _, err = c.Undo("")
+	assert.ErrorIs(t, err, oplog.ErrNoOperation)
+}
+
+func TestCommand_DryRunSkipsBeforeState(t *testing.T) {
+	testData := []struct {
+		Name string
+		Run  func(c *Command) ([]string, error)
+	}{
+		{
+			Name: "Move",
+			Run:  func(c *Command) ([]string, error) { return c.Move([]string{"JIWA-1"}, "Done") },
+		},
+		{
+			Name: "Label",
+			Run:  func(c *Command) ([]string, error) { return c.Label([]string{"JIWA-1"}, []string{"new"}) },
+		},
+		{
+			Name: "Reassign",
+			Run:  func(c *Command) ([]string, error) { return c.Reassign([]string{"JIWA-1"}, "bob") },
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.NotEqual(t, "/rest/api/2/issue/JIWA-1", r.URL.Path, "the issue was read for undo")
+
+				switch r.URL.Path {
+				case "/rest/api/2/issue/JIWA-1/transitions":
+					w.Write([]byte(`{"transitions":[{"id":"31","name":"Done","to":{"name":"Done"}}]}`))
+				case "/rest/api/2/user/assignable/search":
+					w.Write([]byte(`[{"name":"bob"}]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			c := &Command{
+				Config: Config{BaseURL: server.URL, UndoDir: t.TempDir()},
+				Client: &jiwa.Client{
+					Username:       "user",
+					Password:       "pass",
+					BaseURL:        server.URL,
+					APIVersion:     "2",
+					DeploymentType: jiwa.DeploymentServer,
+					HTTPClient:     server.Client(),
+					DryRun:         true,
+					DryRunOutput:   io.Discard,
+				},
+			}
+
+			issues, err := td.Run(c)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"JIWA-1"}, issues)
+		})
+	}
+}
+
+func TestCommand_UndoRetriesFailedChanges(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/rest/api/3/")+" "+string(body))
+
+		if r.URL.Path == "/rest/api/3/issue/JIWA-2" && failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	c := Command{
+		Config: Config{BaseURL: server.URL, UndoDir: t.TempDir()},
+		Client: &jiwa.Client{
+			Username:       "user",
+			Password:       "pass",
+			BaseURL:        server.URL,
+			APIVersion:     "3",
+			DeploymentType: jiwa.DeploymentCloud,
+			HTTPClient:     server.Client(),
+		},
+	}
+
+	summary, description := "Old summary", "old"
+	labels := []string{"old"}
+	_, err := c.oplog().Record("edit", server.URL, []oplog.Change{
+		{
+			Key:            "JIWA-1",
+			Summary:        &summary,
+			Description:    &description,
+			RawDescription: json.RawMessage(`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"old","marks":[{"type":"strong"}]}]}]}`),
+		},
+		{Key: "JIWA-2", Labels: &labels},
+		// recorded by an older version without the document
+		{Key: "JIWA-3", Summary: &summary, Description: &description},
+	})
+	assert.NoError(t, err)
+
+	_, restored, err := c.Undo("")
+	assert.Equal(t, []string{"JIWA-1"}, restored)
+	var bulkErr *BulkError
+	if assert.ErrorAs(t, err, &bulkErr) {
+		assert.Len(t, bulkErr.Failed, 2)
+	}
+	assert.Equal(t, []string{
+		`PUT issue/JIWA-1 {"fields":{"description":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"old","marks":[{"type":"strong"}]}]}]},"summary":"Old summary"}}`,
+		`PUT issue/JIWA-2 {"fields":{"labels":["old"]}}`,
+	}, requests)
+
+	// undoing again only retries what failed
+	requests = nil
+	failing = false
+	op, restored, err := c.Undo("")
+	assert.Equal(t, []string{"JIWA-2"}, restored)
+	assert.Error(t, err)
+	assert.Equal(t, []string{`PUT issue/JIWA-2 {"fields":{"labels":["old"]}}`}, requests)
+	assert.Equal(t, []string{"JIWA-2", "JIWA-3"}, op.Keys())
+}
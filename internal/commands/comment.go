@@ -2,15 +2,24 @@ package commands
 
 import (
 	"context"
+
+	"github.com/catouc/jiwa/internal/oplog"
 )
 
+// Comment adds the comment to the issues, see Move for what is returned.
 func (c *Command) Comment(issues []string, comment string) ([]string, error) {
-	for _, i := range issues {
-		err := c.Client.CommentOnIssue(context.TODO(), i, comment)
+	comment = c.ToMarkup(comment)
+
+	rec := &recorder{}
+	commented, err := c.bulk(issues, func(ctx context.Context, issue string) error {
+		created, err := c.Client.CommentOnIssue(ctx, issue, comment)
 		if err != nil {
-			return nil, err
+			return err
 		}
-	}
 
-	return issues, nil
+		rec.add(oplog.Change{Key: issue, CommentID: created.ID})
+		return nil
+	})
+
+	return commented, c.record("comment", rec, err)
 }
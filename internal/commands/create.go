@@ -4,61 +4,392 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/andygrunwald/go-jira"
 	"github.com/catouc/jiwa/internal/jiwa"
 )
 
-func (c *Command) Create(project, srcFilePath, ticketType, component string) (string, error) {
-	stat, _ := os.Stdin.Stat()
+type CreateInput struct {
+	Project     string
+	SrcFilePath string
+	// TicketType defaults to the project's sub-task type if Parent is set
+	// and to "Task" otherwise.
+	TicketType string
+	Component  string
+	Parent     string
+	// Fields are additional "<field>=<value>" assignments, see Set.
+	Fields []string
+	// Prompt asks on the terminal for required fields that are missing
+	// instead of failing.
+	Prompt bool
+	// StdinConsumed tells Create that stdin was already read, for example
+	// to get the parent key, so the ticket has to come from a file or the
+	// editor instead.
+	StdinConsumed bool
+}
 
-	var summary, description string
-	switch {
-	case srcFilePath != "":
-		fBytes, err := os.ReadFile(srcFilePath)
-		if err != nil {
-			fmt.Printf("failed to read file contents: %s", err)
-			os.Exit(1)
+// Create checks the ticket against the create screen of the project before
+// submitting it, required fields that are missing are added to the editor
+// template or prompted for.
+func (c *Command) Create(input CreateInput) (string, error) {
+	ticketType, meta, fields, err := c.prepareCreate(input)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []jiwa.FieldMeta
+	if validationErr := ValidateCreateFields(meta, fields); validationErr != nil {
+		for _, m := range validationErr.Missing {
+			if m.FieldID != "summary" && m.FieldID != "description" {
+				missing = append(missing, m)
+			}
 		}
+	}
 
-		scanner := bufio.NewScanner(bytes.NewBuffer(fBytes))
+	stat, _ := os.Stdin.Stat()
+	fromEditor := false
 
-		summary, description, err = BuildSummaryAndDescriptionFromScanner(scanner)
+	var text string
+	switch {
+	case input.SrcFilePath != "":
+		fBytes, err := os.ReadFile(input.SrcFilePath)
 		if err != nil {
-			return "", fmt.Errorf("failed to get summary and description: %w", err)
+			return "", fmt.Errorf("failed to read file contents: %w", err)
 		}
-	case (stat.Mode() & os.ModeCharDevice) != 0:
-		var err error
-		summary, description, err = CreateIssueSummaryDescription("")
+		text = string(fBytes)
+	case (stat.Mode()&os.ModeCharDevice) != 0 || input.StdinConsumed:
+		summary, description, err := CreateIssueSummaryDescription(fieldTemplate(missing))
 		if err != nil {
 			return "", fmt.Errorf("failed to get summary and description: %w", err)
 		}
+		text = summary + "\n" + description
+		fromEditor = true
 	case (stat.Mode() & os.ModeCharDevice) == 0:
 		in, err := ReadStdin()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return "", err
+		}
+		text = string(in)
+	}
+
+	t, err := c.parseTicket(meta, fields, text)
+	if err != nil {
+		return "", err
+	}
+
+	validationErr := ValidateCreateFields(meta, t.fields)
+	if validationErr != nil && input.Prompt {
+		ask := validationErr.Missing
+		for _, invalid := range validationErr.Invalid {
+			ask = append(ask, invalid.Field)
 		}
 
-		scanner := bufio.NewScanner(bytes.NewBuffer(in))
-		summary, description, err = BuildSummaryAndDescriptionFromScanner(scanner)
+		err = c.promptForFields(ask, t.fields)
 		if err != nil {
-			return "", fmt.Errorf("failed to get summary and description: %w", err)
+			return "", err
 		}
+
+		validationErr = ValidateCreateFields(meta, t.fields)
+	}
+	if validationErr != nil {
+		if fromEditor {
+			validationErr.SavedTo = saveTicket(t.summary, t.description, validationErr.Missing)
+		}
+		return "", validationErr
 	}
 
-	issue, err := c.Client.CreateIssue(context.TODO(), jiwa.CreateIssueInput{
-		Project:     project,
-		Summary:     summary,
-		Description: description,
-		Labels:      nil,
-		Type:        ticketType,
-		Component:   component,
-	})
+	issue, err := c.Client.CreateIssue(context.TODO(), c.createIssueInput(input, ticketType, t))
 	if err != nil {
+		if fromEditor {
+			if path := saveTicket(t.summary, t.description, nil); path != "" {
+				return "", fmt.Errorf("failed to create issue, the ticket was saved to %s: %w", path, err)
+			}
+		}
 		return "", fmt.Errorf("failed to create issue: %w", err)
 	}
 
 	return issue.Key, nil
 }
+
+// CreateBulk creates a ticket for every document in the file or on stdin,
+// documents are separated by "---" lines and read like the ones of Create.
+// Nothing is created if any of the tickets is invalid. It returns the keys
+// of the created issues, and a *BulkError if Jira rejected some of them.
+func (c *Command) CreateBulk(input CreateInput) ([]string, error) {
+	var text []byte
+	var err error
+	switch {
+	case input.SrcFilePath != "":
+		text, err = os.ReadFile(input.SrcFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file contents: %w", err)
+		}
+	case !input.StdinConsumed:
+		text, err = ReadStdin()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("the tickets need to be passed with --file when stdin is already used")
+	}
+
+	ticketType, meta, fields, err := c.prepareCreate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := splitTicketDocuments(string(text))
+	if len(documents) == 0 {
+		return nil, errors.New("no tickets found")
+	}
+
+	names := make([]string, 0, len(documents))
+	inputs := make([]jiwa.CreateIssueInput, 0, len(documents))
+	bulkErr := &BulkError{Total: len(documents)}
+	for n, doc := range documents {
+		base := make(map[string]interface{}, len(fields))
+		for id, v := range fields {
+			base[id] = v
+		}
+
+		name := fmt.Sprintf("ticket %d", n+1)
+		t, err := c.parseTicket(meta, base, doc)
+		if err == nil {
+			name = fmt.Sprintf("ticket %d (%s)", n+1, t.summary)
+			if validationErr := ValidateCreateFields(meta, t.fields); validationErr != nil {
+				err = validationErr
+			}
+		}
+		if err != nil {
+			bulkErr.Failed = append(bulkErr.Failed, IssueFailure{Key: name, Err: err})
+			continue
+		}
+
+		names = append(names, name)
+		inputs = append(inputs, c.createIssueInput(input, ticketType, t))
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		bulkErr.Skipped = names
+		return nil, bulkErr
+	}
+
+	results, err := c.Client.CreateIssues(context.TODO(), inputs)
+	keys := make([]string, 0, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			bulkErr.Failed = append(bulkErr.Failed, IssueFailure{Key: names[i], Err: r.Err})
+			continue
+		}
+		keys = append(keys, r.Issue.Key)
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		return keys, bulkErr
+	}
+
+	return keys, err
+}
+
+// prepareCreate resolves the ticket type, its create screen and the fields
+// given on the command line that all tickets share.
+func (c *Command) prepareCreate(input CreateInput) (string, []jiwa.FieldMeta, map[string]interface{}, error) {
+	ticketType, err := c.resolveTicketType(input)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	meta, err := c.createMeta(input.Project, ticketType)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	fields, err := c.createFields(meta, input.Fields)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	fields["project"] = map[string]string{"key": input.Project}
+	fields["issuetype"] = map[string]string{"name": ticketType}
+	if input.Component != "" {
+		fields["components"] = []interface{}{map[string]string{"name": input.Component}}
+	}
+	if input.Parent != "" {
+		fields["parent"] = map[string]string{"key": input.Parent}
+	}
+
+	return ticketType, meta, fields, nil
+}
+
+// ticket is a ticket document with its field block resolved.
+type ticket struct {
+	summary     string
+	description string
+	fields      map[string]interface{}
+}
+
+// parseTicket reads the summary, description and field block of a ticket
+// document, the fields are added to fields.
+func (c *Command) parseTicket(meta []jiwa.FieldMeta, fields map[string]interface{}, text string) (ticket, error) {
+	text, assignments := splitFieldBlock(text)
+	scanner := bufio.NewScanner(bytes.NewBufferString(text))
+	summary, description, err := BuildSummaryAndDescriptionFromScanner(scanner)
+	if err != nil {
+		return ticket{}, fmt.Errorf("failed to get summary and description: %w", err)
+	}
+
+	if summary == "" {
+		return ticket{}, errors.New("the summary line needs to be filled at least")
+	}
+
+	templateFields, err := c.createFields(meta, assignments)
+	if err != nil {
+		return ticket{}, err
+	}
+	for id, v := range templateFields {
+		fields[id] = v
+	}
+
+	fields["summary"] = summary
+	if strings.TrimSpace(description) != "" {
+		fields["description"] = description
+	}
+
+	return ticket{summary: summary, description: description, fields: fields}, nil
+}
+
+// createIssueInput turns the ticket into the input of the client.
+func (c *Command) createIssueInput(input CreateInput, ticketType string, t ticket) jiwa.CreateIssueInput {
+	extraFields := make(map[string]interface{}, len(t.fields))
+	for id, v := range t.fields {
+		switch id {
+		case "project", "issuetype", "components", "parent", "summary", "description":
+			continue
+		}
+		extraFields[id] = v
+	}
+
+	return jiwa.CreateIssueInput{
+		Project:     input.Project,
+		Summary:     t.summary,
+		Description: c.ToMarkup(t.description),
+		Labels:      nil,
+		Type:        ticketType,
+		Component:   input.Component,
+		Parent:      input.Parent,
+		Fields:      extraFields,
+	}
+}
+
+// splitTicketDocuments splits text at "---" lines, documents that are
+// empty are dropped.
+func splitTicketDocuments(text string) []string {
+	documents := make([]string, 0)
+	var current strings.Builder
+	flush := func() {
+		if strings.TrimSpace(current.String()) != "" {
+			documents = append(documents, strings.TrimLeft(current.String(), "\n"))
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if strings.TrimSpace(line) == "---" {
+			flush()
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+
+	return documents
+}
+
+// createMeta fetches the create screen of the issue type, instances that
+// don't offer create metadata at all are not validated against.
+func (c *Command) createMeta(project, ticketType string) ([]jiwa.FieldMeta, error) {
+	meta, err := c.Client.GetCreateMeta(context.TODO(), project, ticketType)
+	if errors.Is(err, jiwa.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// createFields resolves field assignments against the create screen, or
+// against all fields if there is no create metadata.
+func (c *Command) createFields(meta []jiwa.FieldMeta, assignments []string) (map[string]interface{}, error) {
+	if len(assignments) == 0 {
+		return make(map[string]interface{}), nil
+	}
+
+	if meta == nil {
+		return c.ResolveFieldValues(assignments)
+	}
+
+	available := make([]jira.Field, 0, len(meta))
+	for _, m := range meta {
+		available = append(available, m.Field())
+	}
+
+	return c.resolveFieldValues(available, assignments)
+}
+
+// saveTicket keeps what was written in the editor around so it isn't lost
+// when the ticket gets rejected, it returns an empty path if saving failed.
+func saveTicket(summary, description string, missing []jiwa.FieldMeta) string {
+	f, err := os.CreateTemp(os.TempDir(), "jiwa-ticket-*")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(summary + "\n" + description + fieldTemplate(missing))
+	if err != nil {
+		return ""
+	}
+
+	return f.Name()
+}
+
+// resolveTicketType fills in the default ticket type, sub-tasks are called
+// differently across Jira versions so their type is looked up on the
+// project.
+func (c *Command) resolveTicketType(input CreateInput) (string, error) {
+	switch {
+	case input.TicketType != "":
+		return input.TicketType, nil
+	case input.Parent == "":
+		return "Task", nil
+	}
+
+	issueTypes, err := c.IssueTypes(input.Project)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up the sub-task type of %s: %w", input.Project, err)
+	}
+
+	for _, it := range issueTypes {
+		if it.Subtask {
+			return it.Name, nil
+		}
+	}
+
+	return "", errors.New("project " + input.Project + " has no sub-task issue type")
+}
+
+func (c *Command) Subtasks(issue string) ([]jira.Subtasks, error) {
+	subtasks, err := c.Client.ListSubtasks(context.TODO(), issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return subtasks, nil
+}
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/catouc/jiwa/internal/jiwa"
+)
+
+// fieldBlockMarker separates the ticket from the fields that still need a
+// value in the editor template.
+const fieldBlockMarker = "# jiwa: required fields, one \"<field>=<value>\" per line, lines starting with # are ignored"
+
+// CreateValidationError lists everything the create screen of a project
+// would reject, so it can be fixed in one go.
+type CreateValidationError struct {
+	Missing []jiwa.FieldMeta
+	Invalid []InvalidFieldValue
+	// SavedTo is the file holding the ticket that failed validation, if any.
+	SavedTo string
+}
+
+// InvalidFieldValue is a value that isn't one of the allowed ones.
+type InvalidFieldValue struct {
+	Field jiwa.FieldMeta
+	Value string
+}
+
+func (e *CreateValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("the ticket is not valid for this project")
+	for _, m := range e.Missing {
+		b.WriteString("\n  missing required field ")
+		b.WriteString(m.Name)
+		if len(m.AllowedValues) != 0 {
+			b.WriteString(", one of: ")
+			b.WriteString(allowedValuesString(m))
+		}
+	}
+	for _, i := range e.Invalid {
+		fmt.Fprintf(&b, "\n  %q is not allowed for %s, one of: %s", i.Value, i.Field.Name, allowedValuesString(i.Field))
+	}
+	if e.SavedTo != "" {
+		fmt.Fprintf(&b, "\nthe ticket was saved to %s, fix it and pass it with --file", e.SavedTo)
+	}
+
+	return b.String()
+}
+
+func allowedValuesString(m jiwa.FieldMeta) string {
+	values := make([]string, 0, len(m.AllowedValues))
+	for _, v := range m.AllowedValues {
+		values = append(values, v.String())
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// ValidateCreateFields checks the fields of a new ticket, keyed by field
+// ID, against the create screen metadata. It returns nil when Jira should
+// accept them.
+func ValidateCreateFields(meta []jiwa.FieldMeta, fields map[string]interface{}) *CreateValidationError {
+	var validationErr CreateValidationError
+	for _, m := range meta {
+		v, ok := fields[m.FieldID]
+		if !ok || v == nil {
+			if m.Required && !m.HasDefaultValue {
+				validationErr.Missing = append(validationErr.Missing, m)
+			}
+			continue
+		}
+
+		if len(m.AllowedValues) == 0 {
+			continue
+		}
+
+		for _, name := range valueNames(v) {
+			if !isAllowed(m, name) {
+				validationErr.Invalid = append(validationErr.Invalid, InvalidFieldValue{Field: m, Value: name})
+			}
+		}
+	}
+
+	if len(validationErr.Missing) == 0 && len(validationErr.Invalid) == 0 {
+		return nil
+	}
+
+	sort.Slice(validationErr.Missing, func(i, j int) bool {
+		return validationErr.Missing[i].Name < validationErr.Missing[j].Name
+	})
+
+	return &validationErr
+}
+
+func isAllowed(m jiwa.FieldMeta, name string) bool {
+	for _, allowed := range m.AllowedValues {
+		if allowed.Matches(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueNames digs the names out of a field value as CoerceFieldValue
+// builds it, raw JSON is not looked into.
+func valueNames(v interface{}) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case map[string]string:
+		for _, k := range []string{"name", "value", "key", "id"} {
+			if value[k] != "" {
+				return []string{value[k]}
+			}
+		}
+	case map[string]interface{}:
+		if name, ok := value["value"].(string); ok {
+			return []string{name}
+		}
+	case []interface{}:
+		names := make([]string, 0, len(value))
+		for _, item := range value {
+			names = append(names, valueNames(item)...)
+		}
+		return names
+	}
+
+	return nil
+}
+
+// fieldTemplate renders the fields missing from the ticket into a block
+// that is appended to the editor template.
+func fieldTemplate(missing []jiwa.FieldMeta) string {
+	if len(missing) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(fieldBlockMarker)
+	b.WriteString("\n")
+	for _, m := range missing {
+		if len(m.AllowedValues) != 0 {
+			b.WriteString("# one of: ")
+			b.WriteString(allowedValuesString(m))
+			b.WriteString("\n")
+		}
+		b.WriteString(m.Name)
+		b.WriteString("=\n")
+	}
+
+	return b.String()
+}
+
+// splitFieldBlock cuts the field block off the description and returns
+// the assignments that were filled in.
+func splitFieldBlock(description string) (string, []string) {
+	before, block, found := strings.Cut(description, fieldBlockMarker)
+	if !found {
+		return description, nil
+	}
+
+	var assignments []string
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		_, value, _ := strings.Cut(line, "=")
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		assignments = append(assignments, line)
+	}
+
+	return strings.TrimRight(before, "\n") + "\n", assignments
+}
+
+// promptForFields asks for a value for every field on the terminal, empty
+// answers leave the field unset.
+func (c *Command) promptForFields(metas []jiwa.FieldMeta, fields map[string]interface{}) error {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return fmt.Errorf("failed to open terminal to prompt for fields: %w", err)
+	}
+	defer tty.Close()
+
+	scanner := bufio.NewScanner(tty)
+	for _, m := range metas {
+		if len(m.AllowedValues) != 0 {
+			fmt.Fprintf(os.Stderr, "%s (%s): ", m.Name, allowedValuesString(m))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: ", m.Name)
+		}
+
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		answer := strings.TrimSpace(scanner.Text())
+		if answer == "" {
+			continue
+		}
+
+		if m.Schema.Type == "user" || m.Schema.Items == "user" {
+			answer, err = c.resolveUserValues(answer)
+			if err != nil {
+				return err
+			}
+		}
+
+		v, err := c.Client.CoerceFieldValue(context.TODO(), m.Field(), answer)
+		if err != nil {
+			return err
+		}
+		fields[m.FieldID] = v
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/catouc/jiwa/internal/credential"
+)
+
+// LoadCredentials asks the credential helper for the secret if the config
+// doesn't have one yet and reports whether the helper provided it.
+func (c *Config) LoadCredentials(ctx context.Context) (bool, error) {
+	if c.CredentialHelper == "" || *c.secret() != "" {
+		return false, nil
+	}
+
+	req, err := c.credential()
+	if err != nil {
+		return false, err
+	}
+
+	cred, err := credential.New(c.CredentialHelper).Get(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	if cred.Password == "" {
+		return false, nil
+	}
+
+	if c.Username == "" && c.Auth != AuthOAuth2 {
+		c.Username = cred.Username
+	}
+	*c.secret() = cred.Password
+
+	return true, nil
+}
+
+// StoreCredentials hands the credentials to the helper after they worked.
+func (c *Config) StoreCredentials(ctx context.Context) error {
+	cred, err := c.credential()
+	if err != nil {
+		return err
+	}
+	cred.Password = *c.secret()
+
+	return credential.New(c.CredentialHelper).Store(ctx, cred)
+}
+
+// EraseCredentials tells the helper to forget credentials Jira rejected.
+func (c *Config) EraseCredentials(ctx context.Context) error {
+	cred, err := c.credential()
+	if err != nil {
+		return err
+	}
+	cred.Password = *c.secret()
+
+	return credential.New(c.CredentialHelper).Erase(ctx, cred)
+}
+
+// ReportCredentials tells the credential helper whether Jira accepted the
+// credentials it supplied, like git does after its first request. Hook it
+// up as jiwa.Client.Authorized.
+func (c *Config) ReportCredentials(ctx context.Context, accepted bool) error {
+	if !accepted {
+		err := c.EraseCredentials(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to erase credentials: %w", err)
+		}
+		return nil
+	}
+
+	err := c.StoreCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store credentials: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) credential() (credential.Credential, error) {
+	username := c.Username
+	if c.Auth == AuthOAuth2 {
+		username = c.OAuth2.ClientID
+	}
+
+	return credential.ForURL(c.BaseURL, username)
+}
+
+// secret points at the config value the credential helper provides, the
+// token for personal access tokens, the client secret for OAuth 2.0 and
+// the password otherwise.
+func (c *Config) secret() *string {
+	switch c.Auth {
+	case AuthPAT:
+		return &c.Token
+	case AuthOAuth2:
+		return &c.OAuth2.ClientSecret
+	case "":
+		if c.Token != "" {
+			return &c.Token
+		}
+		return &c.Password
+	default:
+		return &c.Password
+	}
+}
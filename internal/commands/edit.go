@@ -5,10 +5,16 @@ import (
 	"fmt"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/oplog"
 )
 
 func (c *Command) Edit(issueID string) (string, error) {
-	summary, description, err := GetIssueIntoEditor(c.Client, issueID)
+	before, rawDescription, err := c.Client.GetLatestIssueDescription(context.TODO(), issueID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get summary and description: %w", err)
+	}
+
+	summary, description, err := c.issueIntoEditor(before)
 	if err != nil {
 		return "", fmt.Errorf("failed to get summary and description: %w", err)
 	}
@@ -24,5 +30,13 @@ func (c *Command) Edit(issueID string) (string, error) {
 		return "", fmt.Errorf("failed to update issue: %w", err)
 	}
 
-	return issueID, nil
+	rec := &recorder{}
+	rec.add(oplog.Change{
+		Key:            issueID,
+		Summary:        &before.Fields.Summary,
+		Description:    &before.Fields.Description,
+		RawDescription: rawDescription,
+	})
+
+	return issueID, c.record("edit", rec, nil)
 }
@@ -2,15 +2,31 @@ package commands
 
 import (
 	"context"
+
+	"github.com/catouc/jiwa/internal/oplog"
 )
 
+// Label sets the labels of the issues, see Move for what is returned.
 func (c *Command) Label(issues, labels []string) ([]string, error) {
-	for _, issue := range issues {
-		err := c.Client.LabelIssue(context.TODO(), issue, labels...)
+	rec := &recorder{}
+	labelled, err := c.bulk(issues, func(ctx context.Context, issue string) error {
+		before, err := c.beforeState(ctx, issue)
+		if err != nil {
+			return err
+		}
+
+		err = c.Client.LabelIssue(ctx, issue, labels...)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		var beforeLabels []string
+		if before.Fields != nil {
+			beforeLabels = before.Fields.Labels
 		}
-	}
+		rec.add(oplog.Change{Key: issue, Labels: &beforeLabels})
+		return nil
+	})
 
-	return issues, nil
+	return labelled, c.record("label", rec, err)
 }
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Link links every issue to target. linkType can be the name of the link
+// type or either of its descriptions, using the inward description like
+// "is blocked by" flips the direction of the link.
+func (c *Command) Link(issues []string, linkType, target string) ([]string, error) {
+	linkTypes, err := c.Client.ListIssueLinkTypes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	lt, inward, err := findLinkType(linkTypes, linkType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		from, to := issue, target
+		if inward {
+			from, to = target, issue
+		}
+
+		err := c.Client.LinkIssues(context.TODO(), lt.Name, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+func findLinkType(linkTypes []jira.IssueLinkType, name string) (jira.IssueLinkType, bool, error) {
+	name = strings.ToLower(name)
+
+	valid := make([]string, 0, len(linkTypes))
+	for _, lt := range linkTypes {
+		switch name {
+		case strings.ToLower(lt.Name), strings.ToLower(lt.Outward):
+			return lt, false, nil
+		case strings.ToLower(lt.Inward):
+			return lt, true, nil
+		}
+
+		valid = append(valid, fmt.Sprintf("%q/%q", lt.Outward, lt.Inward))
+	}
+
+	return jira.IssueLinkType{}, false, fmt.Errorf(
+		"could not find %s as a link type, valid link types are: %s",
+		name,
+		strings.Join(valid, ", "),
+	)
+}
+
+// Unlink removes the links between every issue and target, if linkType is
+// set only links of that type are removed.
+func (c *Command) Unlink(issues []string, linkType, target string) ([]string, error) {
+	linkType = strings.ToLower(linkType)
+
+	for _, issue := range issues {
+		links, err := c.Client.ListIssueLinks(context.TODO(), issue)
+		if err != nil {
+			return nil, err
+		}
+
+		removed := 0
+		for _, l := range links {
+			if linkType != "" &&
+				linkType != strings.ToLower(l.Type.Name) &&
+				linkType != strings.ToLower(l.Type.Inward) &&
+				linkType != strings.ToLower(l.Type.Outward) {
+				continue
+			}
+
+			if !linksTo(l, target) {
+				continue
+			}
+
+			err := c.Client.DeleteIssueLink(context.TODO(), l.ID)
+			if err != nil {
+				return nil, err
+			}
+			removed++
+		}
+
+		if removed == 0 {
+			return nil, fmt.Errorf("%s is not linked to %s", issue, target)
+		}
+	}
+
+	return issues, nil
+}
+
+func linksTo(l jira.IssueLink, key string) bool {
+	return (l.InwardIssue != nil && l.InwardIssue.Key == key) ||
+		(l.OutwardIssue != nil && l.OutwardIssue.Key == key)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
 )
 
 type ListInput struct {
@@ -13,6 +14,8 @@ type ListInput struct {
 	Project  string
 	Status   string
 	Labels   []string
+	Limit    int
+	PageSize int
 }
 
 func (c *Command) List(input ListInput) ([]jira.Issue, error) {
@@ -36,8 +39,15 @@ func (c *Command) List(input ListInput) ([]jira.Issue, error) {
 		project = input.Project
 	}
 
+	if c.Offline {
+		return c.listOffline(input, project)
+	}
+
 	jql := fmt.Sprintf("project=%s AND status=\"%s\" %s %s", project, input.Status, user, labelsString)
-	issues, err := c.Client.Search(context.TODO(), jql)
+	issues, err := c.Client.Search(context.TODO(), jql, jiwa.SearchOptions{
+		Limit:    input.Limit,
+		PageSize: input.PageSize,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("could not list issues: %w", err)
 	}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
+	"github.com/catouc/jiwa/internal/mirror"
+)
+
+// ErrOffline is returned by commands that need Jira when running with
+// --offline.
+var ErrOffline = errors.New("not available offline")
+
+// mirrorFields are the fields synced into the mirror.
+var mirrorFields = []string{"*navigable", "comment"}
+
+// mirror returns the mirror of the configured Jira instance.
+func (c *Command) mirror() (*mirror.Store, error) {
+	u, err := url.Parse(c.Config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseURL: %w", err)
+	}
+
+	return &mirror.Store{Dir: filepath.Join(c.Config.MirrorDir, u.Host)}, nil
+}
+
+// Sync mirrors the project, fetching only the issues updated since the last
+// sync unless full is set. A full sync also drops the issues that were
+// deleted or moved to another project since. It returns the amount of
+// issues that were updated.
+func (c *Command) Sync(project string, full bool) (int, error) {
+	if c.Offline {
+		return 0, ErrOffline
+	}
+
+	store, err := c.mirror()
+	if err != nil {
+		return 0, err
+	}
+
+	lastSync, err := store.LastSync(project)
+	if err != nil {
+		return 0, err
+	}
+
+	jql := fmt.Sprintf("project = \"%s\" ORDER BY updated ASC", project)
+	if !lastSync.IsZero() && !full {
+		// relative dates are evaluated by Jira, which saves us from guessing
+		// the time zone of the user's profile. The extra minute makes up for
+		// Jira only comparing minutes.
+		minutes := int(math.Ceil(time.Since(lastSync).Minutes())) + 1
+		jql = fmt.Sprintf("project = \"%s\" AND updated >= -%dm ORDER BY updated ASC", project, minutes)
+	}
+
+	batch, err := store.Batch(project)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	startedAt := time.Now()
+	issues, errs := c.Client.SearchStream(ctx, jql, jiwa.SearchOptions{Fields: mirrorFields})
+
+	synced := 0
+	for issue := range issues {
+		err = batch.Put(issue)
+		if err != nil {
+			return synced, fmt.Errorf("failed to mirror %s: %w", issue.Key, err)
+		}
+		synced++
+	}
+
+	err = <-errs
+	if err != nil {
+		return synced, fmt.Errorf("failed to sync %s: %w", project, err)
+	}
+
+	if full {
+		_, err = batch.Prune()
+		if err != nil {
+			return synced, fmt.Errorf("failed to prune mirror of %s: %w", project, err)
+		}
+	}
+
+	err = batch.Commit(startedAt)
+	if err != nil {
+		return synced, fmt.Errorf("failed to save mirror of %s: %w", project, err)
+	}
+
+	return synced, nil
+}
+
+// Grep searches the summaries, descriptions and comments of the mirrored
+// issues for all words of the text. An empty project searches all mirrored
+// projects.
+func (c *Command) Grep(text, project string) ([]jira.Issue, error) {
+	store, err := c.mirror()
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Grep(project, text)
+}
+
+// listOffline answers List from the mirror, filtering the same way the JQL
+// query does.
+func (c *Command) listOffline(input ListInput, project string) ([]jira.Issue, error) {
+	store, err := c.mirror()
+	if err != nil {
+		return nil, err
+	}
+
+	mirrored, err := store.Issues(project)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]jira.Issue, 0)
+	for _, issue := range mirrored {
+		if !matchesListInput(issue, input) {
+			continue
+		}
+
+		issues = append(issues, issue)
+		if input.Limit > 0 && len(issues) >= input.Limit {
+			break
+		}
+	}
+
+	return issues, nil
+}
+
+func matchesListInput(issue jira.Issue, input ListInput) bool {
+	if issue.Fields == nil {
+		return false
+	}
+
+	if issue.Fields.Status == nil || !strings.EqualFold(issue.Fields.Status.Name, input.Status) {
+		return false
+	}
+
+	switch input.Assignee {
+	case "":
+	case "empty":
+		if issue.Fields.Assignee != nil {
+			return false
+		}
+	default:
+		a := issue.Fields.Assignee
+		if a == nil {
+			return false
+		}
+
+		if !strings.EqualFold(a.Name, input.Assignee) &&
+			!strings.EqualFold(a.EmailAddress, input.Assignee) &&
+			!strings.EqualFold(a.DisplayName, input.Assignee) &&
+			a.AccountID != input.Assignee {
+			return false
+		}
+	}
+
+	if len(input.Labels) == 0 {
+		return true
+	}
+
+	for _, want := range input.Labels {
+		for _, l := range issue.Fields.Labels {
+			if l == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -2,15 +2,30 @@ package commands
 
 import (
 	"context"
+
+	"github.com/catouc/jiwa/internal/oplog"
 )
 
+// Move transitions the issues to the status. It returns the issues that
+// were moved, and a *BulkError if some of them couldn't be.
 func (c *Command) Move(issues []string, status string) ([]string, error) {
-	for _, i := range issues {
-		err := c.Client.TransitionIssue(context.TODO(), i, status)
+	rec := &recorder{}
+	moved, err := c.bulk(issues, func(ctx context.Context, issue string) error {
+		before, err := c.beforeState(ctx, issue)
+		if err != nil {
+			return err
+		}
+
+		err = c.Client.TransitionIssue(ctx, issue, status)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		if before.Fields != nil && before.Fields.Status != nil {
+			rec.add(oplog.Change{Key: issue, Status: &before.Fields.Status.Name})
 		}
-	}
+		return nil
+	})
 
-	return issues, nil
+	return moved, c.record("move", rec, err)
 }
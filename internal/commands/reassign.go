@@ -3,16 +3,43 @@ package commands
 import (
 	"context"
 	"fmt"
+
+	"github.com/catouc/jiwa/internal/oplog"
 )
 
+// Reassign assigns all issues to the user, who is looked up by email,
+// display name or user name among the users assignable to the first issue.
+// See Move for what is returned.
 func (c *Command) Reassign(issues []string, username string) ([]string, error) {
+	if len(issues) == 0 {
+		return issues, nil
+	}
 
-	for _, issue := range issues {
-		err := c.Client.AssignIssue(context.TODO(), issue, username)
+	user, err := c.Client.ResolveUser(context.TODO(), issues[0], username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", username, err)
+	}
+
+	identity := c.Client.UserIdentity(context.TODO(), user)
+	rec := &recorder{}
+	reassigned, err := c.bulk(issues, func(ctx context.Context, issue string) error {
+		before, err := c.beforeState(ctx, issue)
 		if err != nil {
-			return nil, fmt.Errorf("failed to reassign issue %s to %s: %w", issue, username, err)
+			return err
 		}
-	}
 
-	return issues, nil
+		err = c.Client.AssignIssue(ctx, issue, identity)
+		if err != nil {
+			return fmt.Errorf("failed to reassign issue %s to %s: %w", issue, username, err)
+		}
+		if c.Client.DryRun {
+			return nil
+		}
+
+		beforeAssignee := c.assigneeIdentity(ctx, before)
+		rec.add(oplog.Change{Key: issue, Assignee: &beforeAssignee})
+		return nil
+	})
+
+	return reassigned, c.record("reassign", rec, err)
 }
@@ -5,13 +5,20 @@ import (
 	"fmt"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
 )
 
-func (c *Command) Search(jqlQuery string) ([]jira.Issue, error) {
-	issues, err := c.Client.Search(context.TODO(), jqlQuery)
+func (c *Command) Search(jqlQuery string, opts jiwa.SearchOptions) ([]jira.Issue, error) {
+	issues, err := c.Client.Search(context.TODO(), jqlQuery, opts)
 	if err != nil {
 		return nil, fmt.Errorf("could not search issues: %w", err)
 	}
 
 	return issues, nil
 }
+
+// SearchStream hands out the issues as the pages come in from Jira so
+// they can be printed before the whole search is done.
+func (c *Command) SearchStream(jqlQuery string, opts jiwa.SearchOptions) (<-chan jira.Issue, <-chan error) {
+	return c.Client.SearchStream(context.TODO(), jqlQuery, opts)
+}
@@ -0,0 +1,357 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/catouc/jiwa/internal/jiwa"
+	"github.com/catouc/jiwa/internal/secrets"
+	"golang.org/x/term"
+)
+
+// Secrets are the config values `jiwa config encrypt` moves out of the
+// config file into the encrypted secrets file.
+type Secrets struct {
+	Password           string `json:"password,omitempty"`
+	Token              string `json:"token,omitempty"`
+	OAuth2ClientSecret string `json:"oauth2ClientSecret,omitempty"`
+}
+
+// ReadPassphrase returns $JIWA_PASSPHRASE or asks for the passphrase on
+// the terminal without echoing it. Stdin is used if it's the terminal,
+// otherwise it likely holds piped issues and /dev/tty is asked instead.
+func ReadPassphrase(prompt string) ([]byte, error) {
+	if p, set := os.LookupEnv("JIWA_PASSPHRASE"); set {
+		return []byte(p), nil
+	}
+
+	tty := os.Stdin
+	if !term.IsTerminal(int(tty.Fd())) {
+		var err error
+		tty, err = os.OpenFile("/dev/tty", os.O_RDWR, 0)
+		if err != nil {
+			return nil, fmt.Errorf("no terminal to ask for the passphrase, set JIWA_PASSPHRASE: %w", err)
+		}
+		defer tty.Close()
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	passphrase, err := term.ReadPassword(int(tty.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	return passphrase, nil
+}
+
+// LoadSecrets decrypts the secrets file and fills in the secret values of
+// the config. The passphrase is kept to encrypt the OAuth 2.0 tokens with.
+func (c *Config) LoadSecrets(secretsPath string, passphrase []byte) error {
+	s, err := readSecrets(secretsPath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if s.Password != "" {
+		c.Password = s.Password
+	}
+	if s.Token != "" {
+		c.Token = s.Token
+	}
+	if s.OAuth2ClientSecret != "" {
+		c.OAuth2.ClientSecret = s.OAuth2ClientSecret
+	}
+	c.Passphrase = passphrase
+
+	return nil
+}
+
+// EncryptConfig moves the secrets out of the config file into the secrets
+// file, encrypted with the passphrase, and encrypts the OAuth 2.0 tokens.
+// Secrets that were encrypted before are kept unless the config file has
+// new values for them. Everything is read before any file is written, so
+// a failure leaves the files as they were.
+func (c *Config) EncryptConfig(cfgPath, secretsPath string, passphrase []byte) error {
+	var s Secrets
+	_, err := os.Stat(secretsPath)
+	if err == nil {
+		s, err = readSecrets(secretsPath, passphrase)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the tokens are encrypted with the current passphrase already if
+	// this runs again
+	token, err := tokenStore{path: c.OAuth2.TokenFile, passphrase: c.Passphrase}.Load()
+	hasToken := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	raw, err := readRawConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	var oauth2 map[string]json.RawMessage
+	if len(raw["oauth2"]) != 0 {
+		err = json.Unmarshal(raw["oauth2"], &oauth2)
+		if err != nil {
+			return fmt.Errorf("failed to read oauth2 config: %w", err)
+		}
+	}
+
+	for _, secret := range []struct {
+		fields map[string]json.RawMessage
+		key    string
+		value  *string
+	}{
+		{raw, "password", &s.Password},
+		{raw, "token", &s.Token},
+		{oauth2, "clientSecret", &s.OAuth2ClientSecret},
+	} {
+		var v string
+		if len(secret.fields[secret.key]) != 0 {
+			err = json.Unmarshal(secret.fields[secret.key], &v)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", secret.key, err)
+			}
+		}
+		if v != "" {
+			*secret.value = v
+		}
+		delete(secret.fields, secret.key)
+	}
+
+	if oauth2 != nil {
+		raw["oauth2"], err = json.Marshal(oauth2)
+		if err != nil {
+			return fmt.Errorf("failed to marshal oauth2 config: %w", err)
+		}
+	}
+
+	err = writeSecrets(secretsPath, passphrase, s)
+	if err != nil {
+		return err
+	}
+
+	err = writeRawConfig(cfgPath, raw)
+	if err != nil {
+		return err
+	}
+
+	if !hasToken {
+		return nil
+	}
+	return tokenStore{path: c.OAuth2.TokenFile, passphrase: passphrase}.Save(token)
+}
+
+// DecryptConfig is the inverse of EncryptConfig, it puts the secrets back
+// into the config file and removes the secrets file.
+func (c *Config) DecryptConfig(cfgPath, secretsPath string, passphrase []byte) error {
+	s, err := readSecrets(secretsPath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	raw, err := readRawConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	if s.Password != "" {
+		raw["password"], _ = json.Marshal(s.Password)
+	}
+	if s.Token != "" {
+		raw["token"], _ = json.Marshal(s.Token)
+	}
+	if s.OAuth2ClientSecret != "" {
+		oauth2 := make(map[string]json.RawMessage)
+		if len(raw["oauth2"]) != 0 {
+			err = json.Unmarshal(raw["oauth2"], &oauth2)
+			if err != nil {
+				return fmt.Errorf("failed to read oauth2 config: %w", err)
+			}
+		}
+		oauth2["clientSecret"], _ = json.Marshal(s.OAuth2ClientSecret)
+		raw["oauth2"], _ = json.Marshal(oauth2)
+	}
+
+	err = writeRawConfig(cfgPath, raw)
+	if err != nil {
+		return err
+	}
+
+	store := tokenStore{path: c.OAuth2.TokenFile, passphrase: passphrase}
+	token, err := store.Load()
+	if err == nil {
+		store.passphrase = nil
+		err = store.Save(token)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	err = os.Remove(secretsPath)
+	if err != nil {
+		return fmt.Errorf("failed to remove secrets file: %w", err)
+	}
+
+	return nil
+}
+
+func readSecrets(path string, passphrase []byte) (Secrets, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	plaintext, err := secrets.Open(passphrase, b)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("failed to decrypt secrets file: %w", err)
+	}
+
+	var s Secrets
+	err = json.Unmarshal(plaintext, &s)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("failed to unmarshal secrets: %w", err)
+	}
+
+	return s, nil
+}
+
+func writeSecrets(path string, passphrase []byte, s Secrets) error {
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+
+	sealed, err := secrets.Seal(passphrase, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secrets: %w", err)
+	}
+
+	err = writeFile(path, sealed)
+	if err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+
+	return nil
+}
+
+// readRawConfig reads the config file without going through Config so
+// rewriting it keeps exactly the keys the user set.
+func readRawConfig(path string) (map[string]json.RawMessage, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config file: %w", err)
+	}
+
+	return raw, nil
+}
+
+func writeRawConfig(path string, raw map[string]json.RawMessage) error {
+	b, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	err = writeFile(path, append(b, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile replaces the file through a temporary one so it's never left
+// half written, the file is only readable by the user.
+func writeFile(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// tokenStore keeps the OAuth 2.0 tokens in a file, encrypted if the
+// secrets are.
+type tokenStore struct {
+	path       string
+	passphrase []byte
+}
+
+func (s tokenStore) Load() (jiwa.OAuth2Token, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return jiwa.OAuth2Token{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	if secrets.IsSealed(b) {
+		if s.passphrase == nil {
+			return jiwa.OAuth2Token{}, errors.New("the token file is encrypted but there is no passphrase")
+		}
+
+		b, err = secrets.Open(s.passphrase, b)
+		if err != nil {
+			return jiwa.OAuth2Token{}, fmt.Errorf("failed to decrypt token file: %w", err)
+		}
+	}
+
+	var token jiwa.OAuth2Token
+	err = json.Unmarshal(b, &token)
+	if err != nil {
+		return jiwa.OAuth2Token{}, fmt.Errorf("failed to unmarshal token file: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s tokenStore) Save(token jiwa.OAuth2Token) error {
+	if s.passphrase == nil {
+		return jiwa.FileTokenStore{Path: s.path}.Save(token)
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	sealed, err := secrets.Seal(s.passphrase, b)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token: %w", err)
+	}
+
+	err = writeFile(s.path, sealed)
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/catouc/jiwa/internal/jiwa"
+)
+
+func (c *Command) ServerInfo() (jiwa.ServerInfo, error) {
+	info, err := c.Client.ServerInfo(context.TODO())
+	if err != nil {
+		return jiwa.ServerInfo{}, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
+)
+
+// Set updates fields on every issue from assignments like "Story Points=5",
+// fields are looked up by name or ID and the values converted to what
+// their schema expects.
+func (c *Command) Set(issues, assignments []string) ([]string, error) {
+	fields, err := c.ResolveFieldValues(assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		err := c.Client.SetFields(context.TODO(), issue, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// ResolveFieldValues turns "Name=value" assignments into a map of field
+// IDs to values ready to be sent to Jira.
+func (c *Command) ResolveFieldValues(assignments []string) (map[string]interface{}, error) {
+	available, err := c.Client.ListFields(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.resolveFieldValues(available, assignments)
+}
+
+func (c *Command) resolveFieldValues(available []jira.Field, assignments []string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{}, len(assignments))
+	for _, a := range assignments {
+		name, value, found := strings.Cut(a, "=")
+		if !found {
+			return nil, fmt.Errorf("assignment %q is missing a \"=\", use \"<field>=<value>\"", a)
+		}
+
+		field, err := jiwa.ResolveField(available, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Schema.Type == "user" || field.Schema.Items == "user" {
+			value, err = c.resolveUserValues(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		v, err := c.Client.CoerceFieldValue(context.TODO(), field, value)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[field.ID] = v
+	}
+
+	return fields, nil
+}
+
+// Fields lists the fields of the instance, only the custom ones if custom
+// is set.
+func (c *Command) Fields(custom bool) ([]jira.Field, error) {
+	fields, err := c.Client.ListFields(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	if !custom {
+		return fields, nil
+	}
+
+	customFields := make([]jira.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Custom {
+			customFields = append(customFields, f)
+		}
+	}
+
+	return customFields, nil
+}
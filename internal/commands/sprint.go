@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func (c *Command) Boards(project string) ([]jira.Board, error) {
+	boards, err := c.Client.ListBoards(context.TODO(), project)
+	if err != nil {
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+// Sprints lists the sprints of a board, the board can be given by its ID
+// or its name.
+func (c *Command) Sprints(board, state string) ([]jira.Sprint, error) {
+	boardID, err := c.resolveBoard(board)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []string
+	if state != "" {
+		states = strings.Split(state, ",")
+	}
+
+	sprints, err := c.Client.ListSprints(context.TODO(), boardID, states...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sprints, nil
+}
+
+func (c *Command) resolveBoard(board string) (int, error) {
+	id, err := strconv.Atoi(board)
+	if err == nil {
+		return id, nil
+	}
+
+	boards, err := c.Client.ListBoards(context.TODO(), "")
+	if err != nil {
+		return 0, err
+	}
+
+	matches := make([]jira.Board, 0, 1)
+	for _, b := range boards {
+		if strings.EqualFold(b.Name, board) {
+			matches = append(matches, b)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("could not find a board called %s", board)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, strconv.Itoa(m.ID))
+		}
+		return 0, fmt.Errorf("there are several boards called %s, use one of their IDs instead: %s", board, strings.Join(ids, ", "))
+	}
+}
+
+func parseSprintID(sprint string) (int, error) {
+	id, err := strconv.Atoi(sprint)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a sprint ID, `jiwa sprints <board>` lists them", sprint)
+	}
+
+	return id, nil
+}
+
+// SprintAdd moves the issues into the sprint.
+func (c *Command) SprintAdd(issues []string, sprint string) ([]string, error) {
+	id, err := parseSprintID(sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Client.MoveIssuesToSprint(context.TODO(), id, issues)
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+// SprintStart starts the sprint now, dates that were already planned on
+// the sprint win over duration.
+func (c *Command) SprintStart(sprint string, duration time.Duration) error {
+	id, err := parseSprintID(sprint)
+	if err != nil {
+		return err
+	}
+
+	s, err := c.Client.GetSprint(context.TODO(), id)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if s.StartDate != nil && !s.StartDate.IsZero() {
+		start = *s.StartDate
+	}
+
+	end := start.Add(duration)
+	if s.EndDate != nil && !s.EndDate.IsZero() {
+		end = *s.EndDate
+	}
+
+	return c.Client.StartSprint(context.TODO(), id, start, end)
+}
+
+func (c *Command) SprintClose(sprint string) error {
+	id, err := parseSprintID(sprint)
+	if err != nil {
+		return err
+	}
+
+	return c.Client.CloseSprint(context.TODO(), id)
+}
+
+// ParseSprintDuration parses durations like "2w" or "10d" on top of what
+// time.ParseDuration understands.
+func ParseSprintDuration(duration string) (time.Duration, error) {
+	day := 24 * time.Hour
+
+	switch {
+	case strings.HasSuffix(duration, "w"):
+		weeks, err := strconv.Atoi(strings.TrimSuffix(duration, "w"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid sprint duration %q: %w", duration, err)
+		}
+		return time.Duration(weeks) * 7 * day, nil
+	case strings.HasSuffix(duration, "d"):
+		days, err := strconv.Atoi(strings.TrimSuffix(duration, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid sprint duration %q: %w", duration, err)
+		}
+		return time.Duration(days) * day, nil
+	default:
+		return time.ParseDuration(duration)
+	}
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/oplog"
+)
+
+// recorder collects the before-state of the issues a command changed, it
+// is safe for concurrent use.
+type recorder struct {
+	mu      sync.Mutex
+	changes []oplog.Change
+}
+
+func (r *recorder) add(change oplog.Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, change)
+}
+
+// beforeState returns the issue as it is now, to record it before changing
+// it. Nothing is recorded in a dry run, so it returns the zero issue then.
+func (c *Command) beforeState(ctx context.Context, issue string) (jira.Issue, error) {
+	if c.Client.DryRun {
+		return jira.Issue{}, nil
+	}
+
+	return c.Client.GetLatestIssue(ctx, issue)
+}
+
+func (c *Command) oplog() *oplog.Log {
+	return &oplog.Log{Dir: c.Config.UndoDir}
+}
+
+// record saves what the command changed so it can be undone, err is the
+// error of the command and is passed on.
+func (c *Command) record(command string, rec *recorder, err error) error {
+	// nothing was changed in a dry run
+	if len(rec.changes) == 0 || c.Client.DryRun {
+		return err
+	}
+
+	_, recErr := c.oplog().Record(command, c.Config.BaseURL, rec.changes)
+	if recErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to record %s for undo: %w", command, recErr))
+	}
+
+	return err
+}
+
+// Operations lists the recorded operations, oldest first.
+func (c *Command) Operations() ([]oplog.Operation, error) {
+	return c.oplog().List()
+}
+
+// Undo restores the issues changed by the operation to their state before
+// it, an empty id undoes the latest operation that wasn't undone yet.
+// It returns the operation and the issues that were restored, anything
+// that couldn't be reverted is reported in a *BulkError.
+func (c *Command) Undo(id string) (oplog.Operation, []string, error) {
+	log := c.oplog()
+
+	var op oplog.Operation
+	var err error
+	if id == "" {
+		op, err = log.Last()
+	} else {
+		op, err = log.Get(id)
+	}
+	if err != nil {
+		return oplog.Operation{}, nil, err
+	}
+
+	if op.Undone {
+		return op, nil, fmt.Errorf("operation %s was already undone", op.ID)
+	}
+	if op.BaseURL != c.Config.BaseURL {
+		return op, nil, fmt.Errorf("operation %s was done on %s", op.ID, op.BaseURL)
+	}
+
+	restored := make([]string, 0, len(op.Changes))
+	reverted := make([]bool, len(op.Changes))
+	bulkErr := &BulkError{Total: len(op.Changes)}
+	for i, change := range op.Changes {
+		err := c.revert(context.TODO(), change)
+		if err != nil {
+			bulkErr.Failed = append(bulkErr.Failed, IssueFailure{Key: change.Key, Err: err})
+			continue
+		}
+		reverted[i] = true
+		restored = append(restored, change.Key)
+	}
+
+	// only the changes that couldn't be reverted are kept, so undoing
+	// the operation again retries them
+	if !c.Client.DryRun {
+		saved := op
+		saved.Changes = make([]oplog.Change, 0, len(bulkErr.Failed))
+		for i, change := range op.Changes {
+			if !reverted[i] {
+				saved.Changes = append(saved.Changes, change)
+			}
+		}
+		if len(saved.Changes) == 0 {
+			saved.Changes = op.Changes
+			saved.Undone = true
+		}
+
+		err = log.Save(saved)
+		if err != nil {
+			return op, restored, err
+		}
+	}
+
+	if len(bulkErr.Failed) != 0 {
+		return op, restored, bulkErr
+	}
+
+	return op, restored, nil
+}
+
+// revert restores every recorded field of the change, trying all of them
+// even if one fails.
+func (c *Command) revert(ctx context.Context, change oplog.Change) error {
+	var errs []error
+
+	if change.CommentID != "" {
+		err := c.Client.DeleteComment(ctx, change.Key, change.CommentID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not delete comment: %w", err))
+		}
+	}
+
+	if change.Labels != nil {
+		labels := *change.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+
+		err := c.Client.SetFields(ctx, change.Key, map[string]interface{}{"labels": labels})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not restore labels: %w", err))
+		}
+	}
+
+	if change.Assignee != nil {
+		err := c.Client.AssignIssue(ctx, change.Key, *change.Assignee)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not restore assignee: %w", err))
+		}
+	}
+
+	if change.Summary != nil && change.RawDescription != nil {
+		err := c.Client.SetFields(ctx, change.Key, map[string]interface{}{
+			"summary":     *change.Summary,
+			"description": change.RawDescription,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not restore summary and description: %w", err))
+		}
+	} else if change.Summary != nil && change.Description != nil {
+		// older versions only recorded the flattened text, writing it
+		// back would drop the formatting of ADF documents
+		if c.Client.UsesADF(ctx) {
+			errs = append(errs, errors.New("could not restore summary and description: only their plain text was recorded, restoring it would lose the formatting"))
+		} else {
+			err := c.Client.SetSummaryAndDescription(ctx, change.Key, *change.Summary, *change.Description)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not restore summary and description: %w", err))
+			}
+		}
+	}
+
+	if change.Status != nil {
+		err := c.Client.TransitionIssue(ctx, change.Key, *change.Status)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not move back to %s: %w", *change.Status, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// assigneeIdentity returns how the assignee of the issue is referenced, an
+// empty string if it's unassigned.
+func (c *Command) assigneeIdentity(ctx context.Context, issue jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Assignee == nil {
+		return ""
+	}
+
+	return c.Client.UserIdentity(ctx, *issue.Fields.Assignee)
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Users searches for users by email, display name or user name.
+func (c *Command) Users(query string) ([]jira.User, error) {
+	users, err := c.Client.SearchUsers(context.TODO(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// resolveUser looks up a single user, an empty query stands for the
+// authenticated user.
+func (c *Command) resolveUser(query string) (jira.User, error) {
+	if query == "" {
+		return c.Client.Myself(context.TODO())
+	}
+
+	user, err := c.Client.ResolveUser(context.TODO(), "", query)
+	if err != nil {
+		return jira.User{}, fmt.Errorf("failed to look up %s: %w", query, err)
+	}
+
+	return user, nil
+}
+
+// resolveUserValues turns the comma separated users of a user field value
+// into their identities.
+func (c *Command) resolveUserValues(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value[0] == '{' || value[0] == '[' {
+		return value, nil
+	}
+
+	queries := strings.Split(value, ",")
+	identities := make([]string, 0, len(queries))
+	for _, q := range queries {
+		user, err := c.resolveUser(strings.TrimSpace(q))
+		if err != nil {
+			return "", err
+		}
+		identities = append(identities, c.Client.UserIdentity(context.TODO(), user))
+	}
+
+	return strings.Join(identities, ","), nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Watch adds the user to the watchers of every issue, an empty user
+// defaults to the authenticated one.
+func (c *Command) Watch(issues []string, user string) ([]string, error) {
+	watcher, err := c.resolveUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		err := c.Client.AddWatcher(context.TODO(), issue, c.Client.UserIdentity(context.TODO(), watcher))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// Unwatch is the inverse of Watch.
+func (c *Command) Unwatch(issues []string, user string) ([]string, error) {
+	watcher, err := c.resolveUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		err := c.Client.RemoveWatcher(context.TODO(), issue, c.Client.UserIdentity(context.TODO(), watcher))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+func (c *Command) Watchers(issue string) ([]jira.User, error) {
+	if c.Offline {
+		return nil, ErrOffline
+	}
+
+	watchers, err := c.Client.ListWatchers(context.TODO(), issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return watchers, nil
+}
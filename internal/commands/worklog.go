@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/jiwa"
+)
+
+// startedLayouts are the accepted formats for the start of a worklog.
+var startedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"15:04",
+}
+
+// ParseStarted parses the start time of a worklog in the local timezone,
+// a bare time of day refers to today.
+func ParseStarted(started string) (time.Time, error) {
+	if started == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range startedLayouts {
+		t, err := time.ParseInLocation(layout, started, time.Local)
+		if err != nil {
+			continue
+		}
+
+		if layout == "15:04" {
+			now := time.Now()
+			t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("could not parse %q as start time, use a format like \"2006-01-02 15:04\"", started)
+}
+
+func (c *Command) Log(issues []string, timeSpent, comment string, started time.Time) ([]string, error) {
+	comment = c.ToMarkup(comment)
+	for _, issue := range issues {
+		_, err := c.Client.AddWorklog(context.TODO(), issue, jiwa.WorklogInput{
+			TimeSpent: timeSpent,
+			Started:   started,
+			Comment:   comment,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+func (c *Command) Worklogs(issue string) ([]jira.WorklogRecord, error) {
+	worklogs, err := c.Client.ListWorklogs(context.TODO(), issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return worklogs, nil
+}
+
+func (c *Command) DeleteWorklog(issue, id string) error {
+	return c.Client.DeleteWorklog(context.TODO(), issue, id)
+}
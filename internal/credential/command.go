@@ -0,0 +1,65 @@
+package credential
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command runs an external helper, the action is appended as the last
+// argument like git does.
+type Command struct {
+	Spec string
+}
+
+func (h *Command) Get(ctx context.Context, c Credential) (Credential, error) {
+	out, err := h.run(ctx, "get", c)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return c.read(bytes.NewReader(out))
+}
+
+func (h *Command) Store(ctx context.Context, c Credential) error {
+	_, err := h.run(ctx, "store", c)
+	return err
+}
+
+func (h *Command) Erase(ctx context.Context, c Credential) error {
+	_, err := h.run(ctx, "erase", c)
+	return err
+}
+
+func (h *Command) run(ctx context.Context, action string, c Credential) ([]byte, error) {
+	var cmd *exec.Cmd
+	if strings.HasPrefix(h.Spec, "!") {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.Spec[1:]+" \"$@\"", "credential-helper", action)
+	} else {
+		args := strings.Fields(h.Spec)
+		if len(args) == 0 {
+			return nil, errors.New("empty credential helper")
+		}
+		cmd = exec.CommandContext(ctx, args[0], append(args[1:], action)...)
+	}
+
+	var in bytes.Buffer
+	err := c.write(&in)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin = &in
+	// helpers may prompt for a master password on stderr
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %q failed to %s: %w", h.Spec, action, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,122 @@
+// Package credential fetches secrets from helper programs speaking git's
+// credential helper protocol, or from ~/.netrc.
+package credential
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Credential holds the attributes exchanged with a helper, Protocol and
+// Host describe what the credential is for.
+type Credential struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+	Password string
+}
+
+// ForURL returns a credential request for the URL.
+func ForURL(rawURL, username string) (Credential, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	return Credential{
+		Protocol: u.Scheme,
+		Host:     u.Host,
+		Username: username,
+	}, nil
+}
+
+// Helper is a store for credentials.
+type Helper interface {
+	// Get fills in what the helper knows about the credential, a helper
+	// that knows nothing returns it unchanged.
+	Get(ctx context.Context, c Credential) (Credential, error)
+	// Store saves a credential that worked.
+	Store(ctx context.Context, c Credential) error
+	// Erase removes a credential that was rejected.
+	Erase(ctx context.Context, c Credential) error
+}
+
+// New returns the helper described by spec: "netrc" is the built-in netrc
+// helper, anything starting with "!" is run through the shell and
+// everything else is run as a program with its arguments.
+func New(spec string) Helper {
+	if spec == "netrc" {
+		return &Netrc{}
+	}
+
+	return &Command{Spec: spec}
+}
+
+// write encodes the credential in the helper protocol, a list of
+// key=value lines ended by an empty line.
+func (c Credential) write(w io.Writer) error {
+	attrs := []struct{ key, value string }{
+		{"protocol", c.Protocol},
+		{"host", c.Host},
+		{"path", c.Path},
+		{"username", c.Username},
+		{"password", c.Password},
+	}
+
+	var b strings.Builder
+	for _, a := range attrs {
+		if a.value == "" {
+			continue
+		}
+		if strings.ContainsAny(a.value, "\n\x00") {
+			return fmt.Errorf("credential %s contains a newline or NUL", a.key)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", a.key, a.value)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// read decodes the helper's answer on top of c, unknown keys are ignored
+// and "quit=true" is reported as an error.
+func (c Credential) read(r io.Reader) (Credential, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return Credential{}, fmt.Errorf("invalid helper output line %q", line)
+		}
+
+		switch key {
+		case "protocol":
+			c.Protocol = value
+		case "host":
+			c.Host = value
+		case "path":
+			c.Path = value
+		case "username":
+			c.Username = value
+		case "password":
+			c.Password = value
+		case "quit":
+			if value == "true" || value == "1" {
+				return Credential{}, errors.New("credential helper asked to quit")
+			}
+		}
+	}
+
+	return c, scanner.Err()
+}
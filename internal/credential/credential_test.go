@@ -0,0 +1,64 @@
+package credential
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetrc_Get(t *testing.T) {
+	netrc := `# work
+machine jira.example.com login bob password hunter2
+machine other.example.com
+  login alice
+  password secret
+macdef init
+  machine evil.example.com login eve password nope
+
+default login anonymous password guest
+`
+	path := filepath.Join(t.TempDir(), "netrc")
+	err := os.WriteFile(path, []byte(netrc), 0o600)
+	assert.NoError(t, err)
+
+	testData := []struct {
+		Name        string
+		InHost      string
+		InUsername  string
+		OutUsername string
+		OutPassword string
+	}{
+		{Name: "SingleLine", InHost: "jira.example.com", OutUsername: "bob", OutPassword: "hunter2"},
+		{Name: "WithPort", InHost: "jira.example.com:8443", OutUsername: "bob", OutPassword: "hunter2"},
+		{Name: "MultiLine", InHost: "other.example.com", OutUsername: "alice", OutPassword: "secret"},
+		{Name: "OtherUser", InHost: "jira.example.com", InUsername: "carol", OutUsername: "carol", OutPassword: ""},
+		{Name: "MacroSkipped", InHost: "evil.example.com", OutUsername: "anonymous", OutPassword: "guest"},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			h := &Netrc{Path: path}
+
+			c, err := h.Get(context.Background(), Credential{Protocol: "https", Host: td.InHost, Username: td.InUsername})
+			assert.NoError(t, err)
+			assert.Equal(t, td.OutUsername, c.Username)
+			assert.Equal(t, td.OutPassword, c.Password)
+		})
+	}
+}
+
+func TestCommand_Get(t *testing.T) {
+	// the helper echoes the host it was asked for back as the password
+	h := New(`!f() { test "$1" = get || exit 1; while read -r line && [ -n "$line" ]; do case "$line" in host=*) echo "password=${line#host=}";; esac; done; echo username=bob; }; f`)
+
+	c, err := h.Get(context.Background(), Credential{Protocol: "https", Host: "jira.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", c.Username)
+	assert.Equal(t, "jira.example.com", c.Password)
+	assert.Equal(t, "https", c.Protocol)
+}
@@ -0,0 +1,141 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Netrc looks credentials up in a netrc file, it never writes to it so
+// Store and Erase do nothing.
+type Netrc struct {
+	// Path defaults to $NETRC or ~/.netrc.
+	Path string
+}
+
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+func (h *Netrc) Get(_ context.Context, c Credential) (Credential, error) {
+	path, err := h.path()
+	if err != nil {
+		return Credential{}, err
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	hostname := c.Host
+	if h, _, err := net.SplitHostPort(c.Host); err == nil {
+		hostname = h
+	}
+
+	for _, e := range parseNetrc(string(b)) {
+		if e.machine != "" && e.machine != c.Host && e.machine != hostname {
+			continue
+		}
+		if c.Username != "" && e.login != "" && e.login != c.Username {
+			continue
+		}
+
+		if c.Username == "" {
+			c.Username = e.login
+		}
+		c.Password = e.password
+		return c, nil
+	}
+
+	return c, nil
+}
+
+func (h *Netrc) Store(context.Context, Credential) error { return nil }
+
+func (h *Netrc) Erase(context.Context, Credential) error { return nil }
+
+func (h *Netrc) path() (string, error) {
+	if h.Path != "" {
+		return h.Path, nil
+	}
+
+	if p, set := os.LookupEnv("NETRC"); set {
+		return p, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".netrc"), nil
+}
+
+// parseNetrc reads the machine entries of a netrc file, the default entry
+// comes last with an empty machine so it only matches when nothing else
+// does. Macros are skipped.
+func parseNetrc(content string) []netrcEntry {
+	var entries []netrcEntry
+	var defaultEntry *netrcEntry
+	var current *netrcEntry
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		for j := 0; j < len(fields); j++ {
+			if strings.HasPrefix(fields[j], "#") {
+				break
+			}
+
+			next := func() string {
+				if j+1 < len(fields) {
+					j++
+					return fields[j]
+				}
+				return ""
+			}
+
+			switch fields[j] {
+			case "machine":
+				entries = append(entries, netrcEntry{machine: next()})
+				current = &entries[len(entries)-1]
+			case "default":
+				defaultEntry = &netrcEntry{}
+				current = defaultEntry
+			case "login":
+				if current != nil {
+					current.login = next()
+				}
+			case "password":
+				if current != nil {
+					current.password = next()
+				}
+			case "account":
+				next()
+			case "macdef":
+				// a macro runs until the next empty line
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				j = len(fields)
+			}
+		}
+	}
+
+	if defaultEntry != nil {
+		entries = append(entries, *defaultEntry)
+	}
+
+	return entries
+}
@@ -38,6 +38,17 @@ func SetupTmpFileWithEditor(prefill string) (*bufio.Scanner, func(), error) {
 
 	e := exec.Command(editor, tmpFile.Name())
 	e.Stdin = os.Stdin
+
+	// stdin might have been used to pipe in issues, the editor still
+	// needs the terminal though
+	stat, _ := os.Stdin.Stat()
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		tty, err := os.Open("/dev/tty")
+		if err == nil {
+			defer tty.Close()
+			e.Stdin = tty
+		}
+	}
 	e.Stdout = os.Stdout
 	err = e.Run()
 	if err != nil {
@@ -0,0 +1,174 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// maxSprintIssues is the amount of issues the agile API accepts per call
+// when moving issues into a sprint.
+const maxSprintIssues = 50
+
+// agilePage is the pagination envelope of the agile API.
+type agilePage struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	IsLast     bool              `json:"isLast"`
+	Values     []json.RawMessage `json:"values"`
+}
+
+// agileList pages through an agile endpoint and hands every value to fn.
+func (c *Client) agileList(ctx context.Context, endpoint string, params url.Values, fn func(json.RawMessage) error) error {
+	if params == nil {
+		params = url.Values{}
+	}
+
+	startAt := 0
+	for {
+		params.Set("startAt", strconv.Itoa(startAt))
+
+		b, err := c.callAgileAPI(ctx, http.MethodGet, endpoint, params, nil)
+		if err != nil {
+			return err
+		}
+
+		var page agilePage
+		err = json.Unmarshal(b, &page)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		for _, v := range page.Values {
+			err = fn(v)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal value: %w", err)
+			}
+		}
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return nil
+		}
+	}
+}
+
+// ListBoards returns all boards visible to the user, if projectKey is set
+// only the boards of that project.
+func (c *Client) ListBoards(ctx context.Context, projectKey string) ([]jira.Board, error) {
+	params := url.Values{}
+	if projectKey != "" {
+		params.Set("projectKeyOrId", projectKey)
+	}
+
+	boards := make([]jira.Board, 0)
+	err := c.agileList(ctx, "board", params, func(raw json.RawMessage) error {
+		var b jira.Board
+		err := json.Unmarshal(raw, &b)
+		boards = append(boards, b)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list boards: %w", err)
+	}
+
+	return boards, nil
+}
+
+// ListSprints returns the sprints of a board, states can be any of
+// "active", "future" and "closed" and returns all sprints if empty.
+func (c *Client) ListSprints(ctx context.Context, boardID int, states ...string) ([]jira.Sprint, error) {
+	params := url.Values{}
+	if len(states) != 0 {
+		params.Set("state", strings.Join(states, ","))
+	}
+
+	sprints := make([]jira.Sprint, 0)
+	err := c.agileList(ctx, "board/"+strconv.Itoa(boardID)+"/sprint", params, func(raw json.RawMessage) error {
+		var s jira.Sprint
+		err := json.Unmarshal(raw, &s)
+		sprints = append(sprints, s)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sprints of board %d: %w", boardID, err)
+	}
+
+	return sprints, nil
+}
+
+func (c *Client) GetSprint(ctx context.Context, sprintID int) (jira.Sprint, error) {
+	b, err := c.callAgileAPI(ctx, http.MethodGet, "sprint/"+strconv.Itoa(sprintID), nil, nil)
+	if err != nil {
+		return jira.Sprint{}, fmt.Errorf("failed to get sprint %d: %w", sprintID, err)
+	}
+
+	var sprint jira.Sprint
+	err = json.Unmarshal(b, &sprint)
+	if err != nil {
+		return jira.Sprint{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return sprint, nil
+}
+
+// MoveIssuesToSprint moves the issues into the sprint, splitting them into
+// as many calls as the API limit requires.
+func (c *Client) MoveIssuesToSprint(ctx context.Context, sprintID int, keys []string) error {
+	for start := 0; start < len(keys); start += maxSprintIssues {
+		end := min(start+maxSprintIssues, len(keys))
+
+		body, err := json.Marshal(struct {
+			Issues []string `json:"issues"`
+		}{Issues: keys[start:end]})
+		if err != nil {
+			return fmt.Errorf("failed to marshal issues: %w", err)
+		}
+
+		_, err = c.callAgileAPI(ctx, http.MethodPost, "sprint/"+strconv.Itoa(sprintID)+"/issue", nil, bytes.NewBuffer(body))
+		if err != nil {
+			return fmt.Errorf("failed to move issues to sprint %d: %w", sprintID, err)
+		}
+	}
+
+	return nil
+}
+
+// StartSprint activates a future sprint running from start to end.
+func (c *Client) StartSprint(ctx context.Context, sprintID int, start, end time.Time) error {
+	return c.updateSprint(ctx, sprintID, map[string]interface{}{
+		"state":     "active",
+		"startDate": start.Format(time.RFC3339),
+		"endDate":   end.Format(time.RFC3339),
+	})
+}
+
+// CloseSprint completes an active sprint.
+func (c *Client) CloseSprint(ctx context.Context, sprintID int) error {
+	return c.updateSprint(ctx, sprintID, map[string]interface{}{
+		"state": "closed",
+	})
+}
+
+// updateSprint does a partial update of the sprint with the given fields.
+func (c *Client) updateSprint(ctx context.Context, sprintID int, fields map[string]interface{}) error {
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sprint update: %w", err)
+	}
+
+	_, err = c.callAgileAPI(ctx, http.MethodPost, "sprint/"+strconv.Itoa(sprintID), nil, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to update sprint %d: %w", sprintID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ListSprints(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/agile/1.0/board/7/sprint", r.URL.Path)
+		assert.Equal(t, "active,future", r.URL.Query().Get("state"))
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		page := map[string]interface{}{
+			"startAt":    startAt,
+			"maxResults": 2,
+			"isLast":     startAt+2 >= 5,
+		}
+		values := []map[string]interface{}{}
+		for i := startAt; i < startAt+2 && i < 5; i++ {
+			values = append(values, map[string]interface{}{"id": i + 1, "name": fmt.Sprintf("Sprint %d", i+1)})
+		}
+		page["values"] = values
+
+		json.NewEncoder(w).Encode(page)
+	})
+
+	sprints, err := client.ListSprints(context.Background(), 7, "active", "future")
+	assert.NoError(t, err)
+	assert.Len(t, sprints, 5)
+	assert.Equal(t, "Sprint 5", sprints[4].Name)
+}
+
+func TestClient_MoveIssuesToSprint(t *testing.T) {
+	var batches []int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/agile/1.0/sprint/3/issue", r.URL.Path)
+
+		var body struct {
+			Issues []string `json:"issues"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batches = append(batches, len(body.Issues))
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	keys := make([]string, 120)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("JIWA-%d", i+1)
+	}
+
+	err := client.MoveIssuesToSprint(context.Background(), 3, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{50, 50, 20}, batches)
+}
@@ -0,0 +1,192 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// AddAttachment uploads the content of r as filename to the issue.
+// The content is streamed to Jira so large files are never held in memory,
+// which also means the upload can only be sent again if r is an io.Seeker,
+// like a file.
+func (c *Client) AddAttachment(ctx context.Context, key, filename string, r io.Reader) ([]jira.Attachment, error) {
+	endpoint := "issue/" + key + "/attachments"
+
+	if c.DryRun {
+		c.printDryRun(http.MethodPost, c.coreAPI(ctx), endpoint, nil, nil)
+		c.dryRunf("%s: would upload %s", key, filename)
+		return []jira.Attachment{{Filename: filename}}, nil
+	}
+
+	reqURL := c.url(c.coreAPI(ctx), endpoint, nil)
+	sent := false
+	_, b, err := c.send(ctx, http.MethodPost, endpoint, false, func() (*http.Request, []byte, error) {
+		if sent {
+			seeker, ok := r.(io.Seeker)
+			if !ok {
+				return nil, nil, errors.New("the content can't be sent again, upload it from a file instead")
+			}
+			_, err := seeker.Seek(0, io.SeekStart)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to rewind the content: %w", err)
+			}
+		}
+		sent = true
+
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		go func() {
+			part, err := mw.CreateFormFile("file", filename)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			_, err = io.Copy(part, r)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			pw.CloseWithError(mw.Close())
+		}()
+
+		req, err := c.newRequest(ctx, http.MethodPost, reqURL, pr)
+		if err != nil {
+			pr.Close()
+			return nil, nil, err
+		}
+		req.Header.Set("content-type", mw.FormDataContentType())
+		req.Header.Set("X-Atlassian-Token", "no-check")
+
+		return req, nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s to %s: %w", filename, key, err)
+	}
+
+	if c.Cache != nil {
+		c.updateCache(c.cacheIdentity(), http.MethodPost, reqURL, endpoint, nil, nil)
+	}
+
+	var attachments []jira.Attachment
+	err = json.Unmarshal(b, &attachments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// ListAttachments returns the metadata of all attachments on an issue.
+func (c *Client) ListAttachments(ctx context.Context, key string) ([]jira.Attachment, error) {
+	params := url.Values{}
+	params.Set("fields", "attachment")
+
+	b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments of %s: %w", key, err)
+	}
+
+	var issue jira.Issue
+	err = json.Unmarshal(b, &issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	attachments := make([]jira.Attachment, 0)
+	if issue.Fields == nil {
+		return attachments, nil
+	}
+	for _, a := range issue.Fields.Attachments {
+		attachments = append(attachments, *a)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachment returns the metadata of a single attachment.
+func (c *Client) GetAttachment(ctx context.Context, id string) (jira.Attachment, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, "attachment/"+id, nil, nil)
+	if err != nil {
+		return jira.Attachment{}, fmt.Errorf("failed to get attachment %s: %w", id, err)
+	}
+
+	var attachment jira.Attachment
+	err = json.Unmarshal(b, &attachment)
+	if err != nil {
+		return jira.Attachment{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// DownloadAttachment opens the content of the attachment for reading.
+// The caller has to close the returned reader.
+func (c *Client) DownloadAttachment(ctx context.Context, attachment jira.Attachment) (io.ReadCloser, error) {
+	if attachment.Content == "" {
+		return nil, fmt.Errorf("attachment %s has no content URL", attachment.ID)
+	}
+
+	contentURL, err := c.rebaseURL(attachment.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment %s: %w", attachment.ID, err)
+	}
+
+	endpoint := "attachment/" + attachment.ID
+	resp, _, err := c.send(ctx, http.MethodGet, endpoint, true, func() (*http.Request, []byte, error) {
+		req, err := c.newRequest(ctx, http.MethodGet, contentURL, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Del("content-type")
+
+		return req, nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment %s: %w", attachment.ID, err)
+	}
+
+	return resp.Body, nil
+}
+
+// rebaseURL points a URL Jira returned at BaseURL. Jira links to the site
+// itself, which OAuth 2.0 tokens aren't accepted on, and credentials are
+// never sent to another host.
+func (c *Client) rebaseURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", raw, err)
+	}
+
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	if u.Host == base.Host {
+		return raw, nil
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	if !strings.HasPrefix(u.Path, basePath+"/") {
+		u.Path = basePath + u.Path
+		if u.RawPath != "" {
+			u.RawPath = basePath + u.RawPath
+		}
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.User = nil
+
+	return u.String(), nil
+}
@@ -0,0 +1,126 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_AddAttachment(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/JIWA-1/attachments", r.URL.Path)
+		assert.Equal(t, "no-check", r.Header.Get("X-Atlassian-Token"))
+
+		file, header, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer file.Close()
+
+		content, _ := io.ReadAll(file)
+		assert.Equal(t, "build.log", header.Filename)
+		assert.Equal(t, "line one\nline two\n", string(content))
+
+		w.Write([]byte(`[{"id":"10001","filename":"build.log","size":18}]`))
+	})
+
+	attachments, err := client.AddAttachment(context.Background(), "JIWA-1", "build.log", strings.NewReader("line one\nline two\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []jira.Attachment{{ID: "10001", Filename: "build.log", Size: 18}}, attachments)
+}
+
+func TestClient_DownloadAttachment(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "pass", pass)
+		assert.Equal(t, "/secure/attachment/10001/build.log", r.URL.Path)
+
+		w.Write([]byte("log content"))
+	})
+
+	content, err := client.DownloadAttachment(context.Background(), jira.Attachment{
+		ID:      "10001",
+		Content: client.BaseURL + "/secure/attachment/10001/build.log",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer content.Close()
+
+	b, _ := io.ReadAll(content)
+	assert.Equal(t, "log content", string(b))
+}
+
+func TestClient_AddAttachmentRetry(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InContent  io.Reader
+		OutUploads int
+		OutErr     bool
+	}{
+		{Name: "Seekable", InContent: strings.NewReader("content"), OutUploads: 2},
+		{Name: "NotSeekable", InContent: io.MultiReader(strings.NewReader("content")), OutUploads: 1, OutErr: true},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			uploads := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				uploads++
+				file, _, err := r.FormFile("file")
+				if !assert.NoError(t, err) {
+					return
+				}
+				content, _ := io.ReadAll(file)
+				assert.Equal(t, "content", string(content))
+
+				if uploads == 1 {
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte(`[{"id":"10001","filename":"build.log"}]`))
+			})
+			client.RetryPolicy = RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+			_, err := client.AddAttachment(context.Background(), "JIWA-1", "build.log", td.InContent)
+			assert.Equal(t, td.OutErr, err != nil, err)
+			assert.Equal(t, td.OutUploads, uploads)
+		})
+	}
+}
+
+func TestClient_DownloadAttachmentRebased(t *testing.T) {
+	var log bytes.Buffer
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ex/jira/cloud/rest/api/3/attachment/content/10001", r.URL.Path)
+		w.Write([]byte("log content"))
+	})
+	client.BaseURL += "/ex/jira/cloud"
+	client.Trace = &Trace{Log: &log}
+
+	content, err := client.DownloadAttachment(context.Background(), jira.Attachment{
+		ID:      "10001",
+		Content: "https://site.atlassian.net/rest/api/3/attachment/content/10001",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer content.Close()
+
+	b, _ := io.ReadAll(content)
+	assert.Equal(t, "log content", string(b))
+	assert.Contains(t, log.String(), "> GET "+client.BaseURL+"/rest/api/3/attachment/content/10001")
+}
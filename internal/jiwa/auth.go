@@ -0,0 +1,218 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Authenticator adds credentials to the requests of a Client.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Refresher is implemented by authenticators whose credentials can expire.
+// When Jira answers with 401 the client calls Refresh once and sends the
+// request again.
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// BasicAuth authenticates with a user name and a password, or an API
+// token in place of the password on Jira Cloud.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// PersonalAccessToken authenticates with a Data Center personal access
+// token, which is sent as a bearer token.
+type PersonalAccessToken struct {
+	Token string
+}
+
+func (a *PersonalAccessToken) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// SessionAuth logs into Jira Server through rest/auth/1/session and sends
+// the session cookie with every request. Sessions that expired are
+// renewed by logging in again.
+type SessionAuth struct {
+	BaseURL    string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+	// CookieFile keeps the session for the next runs if set, so jiwa
+	// doesn't log in every time.
+	CookieFile string
+
+	mu     sync.Mutex
+	cookie *http.Cookie
+}
+
+func (a *SessionAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cookie == nil {
+		a.cookie = a.loadCookie()
+	}
+	if a.cookie == nil {
+		err := a.login(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.AddCookie(a.cookie)
+	return nil
+}
+
+func (a *SessionAuth) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.login(ctx)
+}
+
+// login has to be called with mu held.
+func (a *SessionAuth) login(ctx context.Context) error {
+	body, err := json.Marshal(map[string]string{
+		"username": a.Username,
+		"password": a.Password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	endpoint := "rest/auth/1/session"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(a.BaseURL, "/")+"/"+endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read login response: %w", err)
+	}
+
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("failed to log in: %w", newAPIError(http.MethodPost, endpoint, resp.StatusCode, b))
+	}
+
+	var session struct {
+		Session struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"session"`
+	}
+	err = json.Unmarshal(b, &session)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if session.Session.Name == "" {
+		return errors.New("failed to log in: Jira did not return a session")
+	}
+
+	a.cookie = &http.Cookie{Name: session.Session.Name, Value: session.Session.Value}
+
+	// a session that can't be saved only costs a login on the next run
+	_ = a.saveCookie()
+	return nil
+}
+
+// savedSession is the content of CookieFile, the session is only used for
+// the site and user it was created for.
+type savedSession struct {
+	BaseURL  string `json:"baseURL"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
+
+// loadCookie returns the session saved in CookieFile, or nil if there is
+// none for this site and user.
+func (a *SessionAuth) loadCookie() *http.Cookie {
+	if a.CookieFile == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(a.CookieFile)
+	if err != nil {
+		return nil
+	}
+
+	var saved savedSession
+	err = json.Unmarshal(b, &saved)
+	if err != nil || saved.BaseURL != a.BaseURL || saved.Username != a.Username || saved.Name == "" {
+		return nil
+	}
+
+	return &http.Cookie{Name: saved.Name, Value: saved.Value}
+}
+
+func (a *SessionAuth) saveCookie() error {
+	if a.CookieFile == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(savedSession{
+		BaseURL:  a.BaseURL,
+		Username: a.Username,
+		Name:     a.cookie.Name,
+		Value:    a.cookie.Value,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(a.CookieFile), 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	err = os.WriteFile(a.CookieFile, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+
+	return nil
+}
+
+// authenticator returns the configured Authenticator, clients that only
+// have Username, Password or Token set use basic auth or a bearer token.
+func (c *Client) authenticator() (Authenticator, error) {
+	switch {
+	case c.Auth != nil:
+		return c.Auth, nil
+	case c.Username != "" && c.Password != "":
+		return &BasicAuth{Username: c.Username, Password: c.Password}, nil
+	case c.Token != "":
+		return &PersonalAccessToken{Token: c.Token}, nil
+	default:
+		return nil, errors.New("either username+password need to be set or token")
+	}
+}
@@ -0,0 +1,160 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SessionAuthRelogin(t *testing.T) {
+	logins := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/auth/1/session" {
+			logins++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"session": map[string]string{"name": "JSESSIONID", "value": "session-" + strconv.Itoa(logins)},
+			})
+			return
+		}
+
+		cookie, err := r.Cookie("JSESSIONID")
+		if err != nil || cookie.Value != "session-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"key":"JIWA-1"}`))
+	})
+	client.Auth = &SessionAuth{BaseURL: client.BaseURL, Username: "user", Password: "pass", HTTPClient: client.HTTPClient}
+
+	issue, err := client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "JIWA-1", issue.Key)
+	assert.Equal(t, 2, logins)
+}
+
+func TestClient_SessionAuthCookieFile(t *testing.T) {
+	logins := 0
+	valid := ""
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/auth/1/session" {
+			logins++
+			valid = "session-" + strconv.Itoa(logins)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"session": map[string]string{"name": "JSESSIONID", "value": valid},
+			})
+			return
+		}
+
+		cookie, err := r.Cookie("JSESSIONID")
+		if err != nil || cookie.Value != valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"key":"JIWA-1"}`))
+	})
+	cookieFile := filepath.Join(t.TempDir(), "session.json")
+
+	// every run starts with a new authenticator
+	run := func() {
+		client.Auth = &SessionAuth{
+			BaseURL:    client.BaseURL,
+			Username:   "user",
+			Password:   "pass",
+			HTTPClient: client.HTTPClient,
+			CookieFile: cookieFile,
+		}
+		_, err := client.GetIssue(context.Background(), "JIWA-1")
+		assert.NoError(t, err)
+	}
+
+	run()
+	run()
+	assert.Equal(t, 1, logins)
+
+	info, err := os.Stat(cookieFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// the session expired on the server
+	valid = "expired"
+	run()
+	run()
+	assert.Equal(t, 2, logins)
+}
+
+func TestClient_OAuth2Refresh(t *testing.T) {
+	store := FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	err := store.Save(OAuth2Token{
+		AccessToken:  "expired",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Hour),
+		CloudID:      "cloud",
+	})
+	assert.NoError(t, err)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			var grant map[string]string
+			json.NewDecoder(r.Body).Decode(&grant)
+			assert.Equal(t, "refresh_token", grant["grant_type"])
+			assert.Equal(t, "refresh-1", grant["refresh_token"])
+			assert.Equal(t, "id", grant["client_id"])
+
+			w.Write([]byte(`{"access_token":"fresh","refresh_token":"refresh-2","expires_in":3600}`))
+			return
+		}
+
+		assert.Equal(t, "Bearer fresh", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"key":"JIWA-1"}`))
+	})
+	client.Auth = &OAuth2{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Store:        store,
+		HTTPClient:   client.HTTPClient,
+		TokenURL:     client.BaseURL + "/oauth/token",
+	}
+
+	_, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+
+	saved, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "fresh", saved.AccessToken)
+	assert.Equal(t, "refresh-2", saved.RefreshToken)
+	assert.Equal(t, "cloud", saved.CloudID)
+}
+
+func TestOAuth2_LookUpAccount(t *testing.T) {
+	store := FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	err := store.Save(OAuth2Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour), CloudID: "cloud"})
+	assert.NoError(t, err)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ex/jira/cloud/rest/api/2/myself", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"accountId":"5b10ac8d82e05b22cc7d4ef5"}`))
+	})
+	auth := &OAuth2{
+		ClientID:   "id",
+		Store:      store,
+		HTTPClient: client.HTTPClient,
+		APIURL:     client.BaseURL + "/ex/jira/",
+	}
+
+	err = auth.LookUpAccount(context.Background())
+	assert.NoError(t, err)
+
+	saved, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", saved.AccountID)
+	assert.Equal(t, "cloud/5b10ac8d82e05b22cc7d4ef5", auth.owner())
+}
@@ -0,0 +1,135 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// BulkCreateLimit is the amount of issues Jira creates per issue/bulk call.
+const BulkCreateLimit = 50
+
+// CreateResult is the outcome of creating one of the issues passed to
+// CreateIssues, either Issue or Err is set.
+type CreateResult struct {
+	Issue jira.Issue
+	Err   error
+}
+
+type bulkCreateRequest struct {
+	IssueUpdates []jira.Issue `json:"issueUpdates"`
+}
+
+type bulkCreateResponse struct {
+	Issues []jira.Issue `json:"issues"`
+	Errors []struct {
+		Status              int             `json:"status"`
+		FailedElementNumber int             `json:"failedElementNumber"`
+		ElementErrors       json.RawMessage `json:"elementErrors"`
+	} `json:"errors"`
+}
+
+// CreateIssues creates all issues through issue/bulk, BulkCreateLimit at a
+// time. The results are in the order of the inputs, issues Jira rejected
+// carry the reason in their Err.
+// The returned error is only set if a call failed as a whole, the results
+// of the issues that weren't created then hold that error as well.
+func (c *Client) CreateIssues(ctx context.Context, inputs []CreateIssueInput) ([]CreateResult, error) {
+	results := make([]CreateResult, len(inputs))
+
+	for start := 0; start < len(inputs); start += BulkCreateLimit {
+		end := min(start+BulkCreateLimit, len(inputs))
+
+		err := c.createChunk(ctx, inputs[start:end], results[start:end])
+		if err != nil {
+			err = fmt.Errorf("failed to create issues: %w", err)
+			for i := start; i < len(results); i++ {
+				results[i] = CreateResult{Err: err}
+			}
+			return results, err
+		}
+	}
+
+	if c.DryRun {
+		for i := range results {
+			results[i].Issue = jira.Issue{Key: dryRunKey(inputs[i].Project, i+1)}
+		}
+	}
+
+	return results, nil
+}
+
+// createChunk creates up to BulkCreateLimit issues and fills in their
+// results.
+func (c *Client) createChunk(ctx context.Context, inputs []CreateIssueInput, results []CreateResult) error {
+	req := bulkCreateRequest{IssueUpdates: make([]jira.Issue, 0, len(inputs))}
+	for _, input := range inputs {
+		req.IssueUpdates = append(req.IssueUpdates, c.newIssue(ctx, input))
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal body: %w", err)
+	}
+
+	// Jira answers with 400 if not a single issue could be created, the
+	// body still tells which issue failed why
+	b, err := c.callAPI(ctx, http.MethodPost, "issue/bulk", nil, bytes.NewBuffer(body))
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		b = apiErr.raw
+	} else if err != nil {
+		return err
+	}
+
+	var resp bulkCreateResponse
+	err = json.Unmarshal(b, &resp)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if apiErr != nil && len(resp.Errors) == 0 {
+		return apiErr
+	}
+
+	failed := make(map[int]bool, len(resp.Errors))
+	for _, e := range resp.Errors {
+		if e.FailedElementNumber < 0 || e.FailedElementNumber >= len(results) {
+			continue
+		}
+		failed[e.FailedElementNumber] = true
+
+		status := e.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		results[e.FailedElementNumber].Err = newAPIError(http.MethodPost, "issue/bulk", status, e.ElementErrors)
+	}
+
+	// the created issues come back in the order they were sent, leaving
+	// out the ones that failed
+	created := resp.Issues
+	for i := range results {
+		if failed[i] {
+			continue
+		}
+
+		if len(created) == 0 && c.DryRun {
+			continue
+		}
+		if len(created) == 0 {
+			results[i].Err = errors.New("jira didn't report the issue as created")
+			continue
+		}
+
+		results[i].Issue = created[0]
+		created = created[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+// bulkCreateHandler creates every issue whose summary doesn't start with
+// "bad", numbering the created ones from 1.
+func bulkCreateHandler(t *testing.T, calls *int32) http.HandlerFunc {
+	var created int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/bulk", r.URL.Path)
+		atomic.AddInt32(calls, 1)
+
+		var req struct {
+			IssueUpdates []jira.Issue `json:"issueUpdates"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(req.IssueUpdates), BulkCreateLimit)
+
+		resp := struct {
+			Issues []jira.Issue      `json:"issues"`
+			Errors []json.RawMessage `json:"errors"`
+		}{Issues: []jira.Issue{}, Errors: []json.RawMessage{}}
+		for i, issue := range req.IssueUpdates {
+			if strings.HasPrefix(issue.Fields.Summary, "bad") {
+				resp.Errors = append(resp.Errors, json.RawMessage(fmt.Sprintf(
+					`{"status":400,"failedElementNumber":%d,"elementErrors":{"errors":{"summary":"not allowed"}}}`, i)))
+				continue
+			}
+			n := atomic.AddInt32(&created, 1)
+			resp.Issues = append(resp.Issues, jira.Issue{Key: fmt.Sprintf("JIWA-%d", n)})
+		}
+
+		if len(resp.Issues) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestClient_CreateIssues(t *testing.T) {
+	testData := []struct {
+		Name      string
+		InSummary func(i int) string
+		InCount   int
+		OutCalls  int32
+		OutKeys   map[int]string
+		OutFailed []int
+	}{
+		{
+			Name:      "Chunked",
+			InSummary: func(i int) string { return fmt.Sprintf("ticket %d", i) },
+			InCount:   BulkCreateLimit + 5,
+			OutCalls:  2,
+			OutKeys:   map[int]string{0: "JIWA-1", BulkCreateLimit: "JIWA-51", BulkCreateLimit + 4: "JIWA-55"},
+		},
+		{
+			Name: "PartialFailure",
+			InSummary: func(i int) string {
+				if i == 1 {
+					return "bad ticket"
+				}
+				return "ticket"
+			},
+			InCount:   3,
+			OutCalls:  1,
+			OutKeys:   map[int]string{0: "JIWA-1", 2: "JIWA-2"},
+			OutFailed: []int{1},
+		},
+		{
+			Name:      "AllFailed",
+			InSummary: func(i int) string { return "bad ticket" },
+			InCount:   2,
+			OutCalls:  1,
+			OutKeys:   map[int]string{},
+			OutFailed: []int{0, 1},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			client := newTestClient(t, bulkCreateHandler(t, &calls))
+
+			inputs := make([]CreateIssueInput, 0, td.InCount)
+			for i := 0; i < td.InCount; i++ {
+				inputs = append(inputs, CreateIssueInput{Project: "JIWA", Type: "Task", Summary: td.InSummary(i)})
+			}
+
+			results, err := client.CreateIssues(context.Background(), inputs)
+			assert.NoError(t, err)
+			assert.Len(t, results, td.InCount)
+			assert.Equal(t, td.OutCalls, calls)
+
+			for i, key := range td.OutKeys {
+				assert.NoError(t, results[i].Err)
+				assert.Equal(t, key, results[i].Issue.Key)
+			}
+			for _, i := range td.OutFailed {
+				assert.ErrorIs(t, results[i].Err, ErrValidation)
+				assert.ErrorContains(t, results[i].Err, "summary: not allowed")
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package jiwa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMetadataTTL is how long metadata is served from the cache
+	// without asking Jira.
+	DefaultMetadataTTL = 24 * time.Hour
+	// DefaultIssueTTL is how long issues and searches are served from the
+	// cache without asking Jira.
+	DefaultIssueTTL = time.Minute
+)
+
+// Cache keeps the responses to GET requests on disk so consecutive runs of
+// jiwa don't have to fetch the same data again. Entries are served as is
+// for their TTL, after that they are revalidated with their ETag or
+// Last-Modified header.
+// Responses are keyed by URL and the identity of the credentials, so users
+// sharing a cache directory never see each other's data.
+type Cache struct {
+	Dir string `json:"dir"`
+	// MetadataTTL applies to fields, issue types, projects, statuses and
+	// the like, which rarely change. Zero means DefaultMetadataTTL and a
+	// negative TTL revalidates on every request.
+	MetadataTTL time.Duration `json:"metadataTTL"`
+	// IssueTTL applies to everything else, like issues and searches.
+	// Zero means DefaultIssueTTL.
+	IssueTTL time.Duration `json:"issueTTL"`
+}
+
+// DefaultCacheDir returns jiwa's directory in the user's cache directory,
+// $XDG_CACHE_HOME/jiwa on Linux.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "jiwa"), nil
+}
+
+// Clear removes every cached response.
+func (c *Cache) Clear() error {
+	err := os.RemoveAll(c.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	return nil
+}
+
+type cacheClass string
+
+const (
+	cacheClassMetadata cacheClass = "metadata"
+	cacheClassIssue    cacheClass = "issue"
+)
+
+// metadataEndpoints are the endpoints that rarely change, matched by prefix.
+var metadataEndpoints = []string{
+	"field",
+	"issuetype",
+	"issueLinkType",
+	"issue/createmeta",
+	"priority",
+	"resolution",
+	"status",
+	"project",
+	"serverInfo",
+	"myself",
+}
+
+// endpointClass sorts the endpoint into the class its TTL is taken from.
+func endpointClass(endpoint string) cacheClass {
+	for _, prefix := range metadataEndpoints {
+		if endpoint == prefix || strings.HasPrefix(endpoint, prefix+"/") {
+			return cacheClassMetadata
+		}
+	}
+
+	return cacheClassIssue
+}
+
+func (c *Cache) ttl(class cacheClass) time.Duration {
+	switch {
+	case class == cacheClassMetadata && c.MetadataTTL != 0:
+		return c.MetadataTTL
+	case class == cacheClassMetadata:
+		return DefaultMetadataTTL
+	case c.IssueTTL != 0:
+		return c.IssueTTL
+	default:
+		return DefaultIssueTTL
+	}
+}
+
+type cacheEntry struct {
+	URL          string     `json:"url"`
+	Class        cacheClass `json:"class"`
+	StoredAt     time.Time  `json:"storedAt"`
+	ETag         string     `json:"etag,omitempty"`
+	LastModified string     `json:"lastModified,omitempty"`
+	Body         []byte     `json:"body"`
+}
+
+func (e *cacheEntry) fresh(ttl time.Duration) bool {
+	return time.Since(e.StoredAt) < ttl
+}
+
+// revalidatable reports whether Jira can tell us that the entry is still
+// up to date.
+func (e *cacheEntry) revalidatable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// setConditional makes the request conditional on the entry having changed.
+func (e *cacheEntry) setConditional(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// path keeps every class in its own directory, so invalidating a class
+// doesn't need to look at the entries.
+func (c *Cache) path(identity string, class cacheClass, reqURL string) string {
+	sum := sha256.Sum256([]byte(reqURL))
+	return filepath.Join(c.Dir, identity, string(class), hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached response to reqURL, or nil if there is none.
+// Unreadable entries are treated as missing.
+func (c *Cache) load(identity string, class cacheClass, reqURL string) *cacheEntry {
+	b, err := os.ReadFile(c.path(identity, class, reqURL))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	err = json.Unmarshal(b, &entry)
+	if err != nil || entry.URL != reqURL {
+		return nil
+	}
+
+	return &entry
+}
+
+// store writes the entry, replacing the file atomically so concurrent runs
+// never read half written entries.
+func (c *Cache) store(identity string, entry *cacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	p := c.path(identity, entry.Class, entry.URL)
+	err = os.MkdirAll(filepath.Dir(p), 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return os.Rename(f.Name(), p)
+}
+
+// invalidate drops the issue class entries of identity, called after we
+// changed something in Jira. Metadata is left alone since jiwa never
+// changes it.
+func (c *Cache) invalidate(identity string) error {
+	err := os.RemoveAll(filepath.Join(c.Dir, identity, string(cacheClassIssue)))
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cache: %w", err)
+	}
+
+	return nil
+}
+
+// cacheIdentity identifies the credentials the client uses without
+// revealing them, it names the directory their responses are cached in.
+// It is empty if the user behind the credentials isn't known, responses
+// are not cached then.
+func (c *Client) cacheIdentity() string {
+	var id string
+	auth, err := c.authenticator()
+	switch a := auth.(type) {
+	case *BasicAuth:
+		id = "basic:" + a.Username
+	case *PersonalAccessToken:
+		id = "pat:" + a.Token
+	case *SessionAuth:
+		id = "session:" + a.Username
+	case *OAuth2:
+		// everyone logging in through the same OAuth app shares its
+		// client ID, the token is what belongs to a single user
+		owner := a.owner()
+		if owner == "" {
+			return ""
+		}
+		id = "oauth2:" + owner
+	default:
+		if err == nil {
+			id = fmt.Sprintf("%T", auth)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(c.BaseURL + "\n" + id))
+	return hex.EncodeToString(sum[:16])
+}
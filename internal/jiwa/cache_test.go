@@ -0,0 +1,168 @@
+package jiwa
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Cache(t *testing.T) {
+	gets, revalidated := 0, 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		gets++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"key":"JIWA-1","fields":{"summary":"cached"}}`))
+	})
+	client.Cache = &Cache{Dir: t.TempDir()}
+
+	issue, err := client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", issue.Fields.Summary)
+
+	// fresh entries are served without asking Jira
+	_, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, gets)
+
+	// stale entries are revalidated
+	client.Cache.IssueTTL = -time.Second
+	issue, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", issue.Fields.Summary)
+	assert.Equal(t, 2, gets)
+	assert.Equal(t, 1, revalidated)
+
+	// changing the issue drops the cached version but keeps metadata
+	client.Cache.IssueTTL = 0
+	_, err = client.Myself(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, gets)
+	err = client.LabelIssue(context.Background(), "JIWA-1", "cache")
+	assert.NoError(t, err)
+	_, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	_, err = client.Myself(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, gets)
+	assert.Equal(t, 1, revalidated)
+
+	// other credentials never see the cached responses
+	client.Username = "other"
+	_, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, gets)
+}
+
+func TestClient_GetLatestIssue(t *testing.T) {
+	summary := "before"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"key":"JIWA-1","fields":{"summary":"` + summary + `"}}`))
+	})
+	client.Cache = &Cache{Dir: t.TempDir()}
+
+	_, err := client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+
+	// someone else changes the issue while it is cached
+	summary = "after"
+	issue, err := client.GetLatestIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "after", issue.Fields.Summary)
+
+	// and the cache picks up the latest version
+	issue, err = client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "after", issue.Fields.Summary)
+}
+
+func TestClient_AuthorizedIgnoresCache(t *testing.T) {
+	status := http.StatusOK
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"key":"JIWA-1"}`))
+	})
+	client.Cache = &Cache{Dir: t.TempDir()}
+
+	_, err := client.Myself(context.Background())
+	assert.NoError(t, err)
+
+	// the password changed, the cached answer must not vouch for it
+	status = http.StatusUnauthorized
+	var reported []bool
+	client.Authorized = func(ok bool) { reported = append(reported, ok) }
+
+	_, err = client.Myself(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, reported)
+
+	_, err = client.GetLatestIssue(context.Background(), "JIWA-1")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, []bool{false}, reported)
+}
+
+func TestClient_cacheIdentityOAuth2(t *testing.T) {
+	testData := []struct {
+		Name        string
+		InAccountID string
+		OutCached   bool
+	}{
+		{Name: "Alice", InAccountID: "alice", OutCached: true},
+		{Name: "Bob", InAccountID: "bob", OutCached: true},
+		{Name: "UnknownAccount", InAccountID: "", OutCached: false},
+	}
+
+	identities := make(map[string]string)
+	for _, td := range testData {
+		store := FileTokenStore{Path: filepath.Join(t.TempDir(), "token.json")}
+		err := store.Save(OAuth2Token{AccessToken: "token", CloudID: "cloud", AccountID: td.InAccountID})
+		assert.NoError(t, err)
+
+		// everyone uses the same OAuth app
+		client := &Client{BaseURL: "https://api.atlassian.com/ex/jira/cloud", Auth: &OAuth2{ClientID: "app", Store: store}}
+		identity := client.cacheIdentity()
+		assert.Equal(t, td.OutCached, identity != "", td.Name)
+
+		for name, other := range identities {
+			assert.NotEqual(t, other, identity, td.Name+" shares the cache with "+name)
+		}
+		identities[td.Name] = identity
+	}
+}
+
+func TestEndpointClass(t *testing.T) {
+	testData := []struct {
+		Name     string
+		In       string
+		OutClass cacheClass
+	}{
+		{Name: "Fields", In: "field", OutClass: cacheClassMetadata},
+		{Name: "CreateMeta", In: "issue/createmeta/JIWA/issuetypes", OutClass: cacheClassMetadata},
+		{Name: "Project", In: "project/JIWA", OutClass: cacheClassMetadata},
+		{Name: "Issue", In: "issue/JIWA-1", OutClass: cacheClassIssue},
+		{Name: "Search", In: "search", OutClass: cacheClassIssue},
+		{Name: "PrefixOfOtherEndpoint", In: "fieldset", OutClass: cacheClassIssue},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.OutClass, endpointClass(td.In))
+		})
+	}
+}
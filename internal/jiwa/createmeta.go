@@ -0,0 +1,209 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// FieldMeta describes a field on the create screen of an issue type.
+type FieldMeta struct {
+	FieldID         string           `json:"fieldId"`
+	Key             string           `json:"key"`
+	Name            string           `json:"name"`
+	Required        bool             `json:"required"`
+	HasDefaultValue bool             `json:"hasDefaultValue"`
+	Schema          jira.FieldSchema `json:"schema"`
+	AllowedValues   []AllowedValue   `json:"allowedValues"`
+}
+
+// AllowedValue is one of the values a field with a fixed set of options
+// accepts, depending on the field type it has a name, a value or a key.
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Key   string `json:"key"`
+}
+
+// String returns the human readable form of the value.
+func (v AllowedValue) String() string {
+	switch {
+	case v.Name != "":
+		return v.Name
+	case v.Value != "":
+		return v.Value
+	case v.Key != "":
+		return v.Key
+	default:
+		return v.ID
+	}
+}
+
+// Matches tells whether s names this value, case insensitive.
+func (v AllowedValue) Matches(s string) bool {
+	for _, candidate := range []string{v.ID, v.Name, v.Value, v.Key} {
+		if candidate != "" && strings.EqualFold(candidate, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Field returns the field in the shape ListFields returns it, so it can be
+// used with ResolveField and CoerceFieldValue.
+func (m FieldMeta) Field() jira.Field {
+	return jira.Field{
+		ID:     m.FieldID,
+		Key:    m.Key,
+		Name:   m.Name,
+		Custom: strings.HasPrefix(m.FieldID, "customfield_"),
+		Schema: m.Schema,
+	}
+}
+
+// createMetaPage is the pagination envelope of the createmeta endpoints,
+// Cloud puts the values under issueTypes or fields, Data Center under values.
+type createMetaPage struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	IsLast     bool              `json:"isLast"`
+	Values     []json.RawMessage `json:"values"`
+	IssueTypes []json.RawMessage `json:"issueTypes"`
+	Fields     []json.RawMessage `json:"fields"`
+}
+
+// GetCreateMeta returns the fields of the create screen for the issue type
+// in the project. Instances that don't know the paged createmeta endpoints
+// yet are asked through the old issue/createmeta endpoint instead.
+func (c *Client) GetCreateMeta(ctx context.Context, project, issueType string) ([]FieldMeta, error) {
+	meta, err := c.getCreateMeta(ctx, project, issueType)
+	if errors.Is(err, ErrNotFound) {
+		meta, err = c.getLegacyCreateMeta(ctx, project, issueType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create metadata for %s in %s: %w", issueType, project, err)
+	}
+
+	return meta, nil
+}
+
+func (c *Client) getCreateMeta(ctx context.Context, project, issueType string) ([]FieldMeta, error) {
+	var typeID string
+	endpoint := "issue/createmeta/" + project + "/issuetypes"
+	err := c.createMetaList(ctx, endpoint, func(raw json.RawMessage) error {
+		var it jira.IssueType
+		err := json.Unmarshal(raw, &it)
+		if err == nil && typeID == "" && strings.EqualFold(it.Name, issueType) {
+			typeID = it.ID
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if typeID == "" {
+		return nil, fmt.Errorf("project %s has no issue type called %s", project, issueType)
+	}
+
+	meta := make([]FieldMeta, 0)
+	err = c.createMetaList(ctx, endpoint+"/"+typeID, func(raw json.RawMessage) error {
+		var m FieldMeta
+		err := json.Unmarshal(raw, &m)
+		meta = append(meta, m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// createMetaList pages through a createmeta endpoint and hands every value
+// to fn.
+func (c *Client) createMetaList(ctx context.Context, endpoint string, fn func(json.RawMessage) error) error {
+	params := url.Values{}
+	startAt := 0
+	for {
+		params.Set("startAt", strconv.Itoa(startAt))
+
+		b, err := c.callAPI(ctx, http.MethodGet, endpoint, params, nil)
+		if err != nil {
+			return err
+		}
+
+		var page createMetaPage
+		err = json.Unmarshal(b, &page)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		values := append(append(page.Values, page.IssueTypes...), page.Fields...)
+		for _, v := range values {
+			err = fn(v)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal value: %w", err)
+			}
+		}
+
+		startAt += len(values)
+		if page.IsLast || len(values) == 0 || (page.Total > 0 && startAt >= page.Total) {
+			return nil
+		}
+	}
+}
+
+func (c *Client) getLegacyCreateMeta(ctx context.Context, project, issueType string) ([]FieldMeta, error) {
+	params := url.Values{}
+	params.Set("projectKeys", project)
+	params.Set("issuetypeNames", issueType)
+	params.Set("expand", "projects.issuetypes.fields")
+
+	b, err := c.callAPI(ctx, http.MethodGet, "issue/createmeta", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Projects []struct {
+			IssueTypes []struct {
+				Name   string               `json:"name"`
+				Fields map[string]FieldMeta `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+	err = json.Unmarshal(b, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	for _, p := range resp.Projects {
+		for _, it := range p.IssueTypes {
+			if !strings.EqualFold(it.Name, issueType) {
+				continue
+			}
+
+			meta := make([]FieldMeta, 0, len(it.Fields))
+			for id, m := range it.Fields {
+				m.FieldID = id
+				meta = append(meta, m)
+			}
+			sort.Slice(meta, func(i, j int) bool { return meta[i].FieldID < meta[j].FieldID })
+			return meta, nil
+		}
+	}
+
+	return nil, fmt.Errorf("project %s has no issue type called %s", project, issueType)
+}
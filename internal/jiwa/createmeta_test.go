@@ -0,0 +1,52 @@
+package jiwa
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetCreateMeta(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/createmeta/JIWA/issuetypes":
+			w.Write([]byte(`{"startAt":0,"maxResults":50,"total":2,"issueTypes":[{"id":"1","name":"Bug"},{"id":"3","name":"Task"}]}`))
+		case "/rest/api/2/issue/createmeta/JIWA/issuetypes/3":
+			w.Write([]byte(`{"startAt":0,"maxResults":50,"total":2,"fields":[
+				{"fieldId":"summary","name":"Summary","required":true},
+				{"fieldId":"components","name":"Component/s","required":true,"allowedValues":[{"id":"10","name":"Backend"}]}
+			]}`))
+		default:
+			t.Errorf("unexpected call to %s", r.URL.Path)
+		}
+	})
+
+	meta, err := client.GetCreateMeta(context.Background(), "JIWA", "task")
+	assert.NoError(t, err)
+	assert.Len(t, meta, 2)
+	assert.Equal(t, "components", meta[1].FieldID)
+	assert.True(t, meta[1].AllowedValues[0].Matches("backend"))
+}
+
+func TestClient_GetCreateMetaLegacy(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/createmeta" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		assert.Equal(t, "JIWA", r.URL.Query().Get("projectKeys"))
+		w.Write([]byte(`{"projects":[{"key":"JIWA","issuetypes":[{"name":"Task","fields":{
+			"summary":{"name":"Summary","required":true},
+			"priority":{"name":"Priority","required":true,"allowedValues":[{"id":"1","name":"High"}]}
+		}}]}]}`))
+	})
+
+	meta, err := client.GetCreateMeta(context.Background(), "JIWA", "Task")
+	assert.NoError(t, err)
+	assert.Len(t, meta, 2)
+	assert.Equal(t, "priority", meta[0].FieldID)
+	assert.Equal(t, "summary", meta[1].FieldID)
+}
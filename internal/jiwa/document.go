@@ -0,0 +1,165 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/catouc/jiwa/internal/adf"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// UsesADF reports whether descriptions and comments are exchanged as
+// Atlassian Document Format, which is the case for API version 3.
+func (c *Client) UsesADF(ctx context.Context) bool {
+	return c.apiVersion(ctx) == "3"
+}
+
+// richText returns the value to send for a rich text field like a
+// comment body.
+func (c *Client) richText(ctx context.Context, text string) interface{} {
+	if c.UsesADF(ctx) {
+		return adf.FromText(text)
+	}
+
+	return text
+}
+
+// encodeRichTextFields returns a copy of fields where description and
+// environment are swapped for their ADF documents if the API needs them.
+// jira.IssueFields only holds strings for those so the documents are put
+// into Unknowns, which gets merged into the fields on marshalling.
+func (c *Client) encodeRichTextFields(ctx context.Context, fields *jira.IssueFields) *jira.IssueFields {
+	if !c.UsesADF(ctx) || fields == nil {
+		return fields
+	}
+
+	encoded := *fields
+	encoded.Unknowns = tcontainer.NewMarshalMap()
+	for k, v := range fields.Unknowns {
+		encoded.Unknowns[k] = v
+	}
+
+	if encoded.Description != "" {
+		encoded.Unknowns["description"] = adf.FromText(encoded.Description)
+		encoded.Description = ""
+	}
+	if encoded.Environment != "" {
+		encoded.Unknowns["environment"] = adf.FromText(encoded.Environment)
+		encoded.Environment = ""
+	}
+
+	return &encoded
+}
+
+// unmarshalIssue decodes an issue, rendering ADF documents in rich text
+// fields to text first since jira.IssueFields expects plain strings there.
+func (c *Client) unmarshalIssue(ctx context.Context, b []byte, issue *jira.Issue) error {
+	if c.UsesADF(ctx) {
+		var err error
+		b, err = flattenIssueDocuments(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(b, issue)
+}
+
+func flattenIssueDocuments(b []byte) ([]byte, error) {
+	var issue map[string]json.RawMessage
+	err := json.Unmarshal(b, &issue)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if len(issue["fields"]) == 0 {
+		return b, nil
+	}
+	err = json.Unmarshal(issue["fields"], &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"description", "environment"} {
+		if raw, ok := fields[name]; ok {
+			fields[name], err = flattenDocument(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if raw, ok := fields["comment"]; ok && !isNull(raw) {
+		var comments map[string]json.RawMessage
+		err = json.Unmarshal(raw, &comments)
+		if err != nil {
+			return nil, err
+		}
+
+		comments["comments"], err = flattenDocumentsIn(comments["comments"], "body")
+		if err != nil {
+			return nil, err
+		}
+
+		fields["comment"], err = json.Marshal(comments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issue["fields"], err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(issue)
+}
+
+// flattenDocumentsIn renders the document under key for every object in
+// the JSON array list.
+func flattenDocumentsIn(list json.RawMessage, key string) (json.RawMessage, error) {
+	if len(list) == 0 || isNull(list) {
+		return list, nil
+	}
+
+	var objects []map[string]json.RawMessage
+	err := json.Unmarshal(list, &objects)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range objects {
+		if raw, ok := o[key]; ok {
+			o[key], err = flattenDocument(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(objects)
+}
+
+// flattenDocument turns an ADF document into a JSON string, anything that
+// isn't an object is passed through.
+func flattenDocument(raw json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return raw, nil
+	}
+
+	var doc adf.Node
+	err := json.Unmarshal(trimmed, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(adf.ToText(doc))
+}
+
+func isNull(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "null"
+}
@@ -0,0 +1,76 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetIssueADF(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/JIWA-1", r.URL.Path)
+		w.Write([]byte(`{
+			"key": "JIWA-1",
+			"fields": {
+				"summary": "Broken build",
+				"description": {"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"it is ","marks":[]},{"type":"text","text":"broken","marks":[{"type":"strong"}]}]}]},
+				"comment": {"comments": [{"id": "1", "body": {"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"on it"}]}]}}]}
+			}
+		}`))
+	})
+	client.APIVersion = "3"
+
+	issue, err := client.GetIssue(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Broken build", issue.Fields.Summary)
+	assert.Equal(t, "it is **broken**", issue.Fields.Description)
+	assert.Equal(t, "on it", issue.Fields.Comments.Comments[0].Body)
+}
+
+func TestClient_UpdateIssueADF(t *testing.T) {
+	testData := []struct {
+		Name           string
+		InAPIVersion   string
+		OutDescription string
+	}{
+		{
+			Name:           "Version2SendsPlainText",
+			InAPIVersion:   "2",
+			OutDescription: `"some *text*"`,
+		},
+		{
+			Name:           "Version3SendsDocument",
+			InAPIVersion:   "3",
+			OutDescription: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"some "},{"type":"text","text":"text","marks":[{"type":"em"}]}]}]}`,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+
+				var body struct {
+					Fields map[string]json.RawMessage `json:"fields"`
+				}
+				err := json.Unmarshal(b, &body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, td.OutDescription, string(body.Fields["description"]))
+				assert.JSONEq(t, `"Summary"`, string(body.Fields["summary"]))
+			})
+			client.APIVersion = td.InAPIVersion
+
+			fields := &jira.IssueFields{Summary: "Summary", Description: "some *text*"}
+			err := client.UpdateIssue(context.Background(), jira.Issue{Key: "JIWA-1", Fields: fields})
+			assert.NoError(t, err)
+			assert.Equal(t, "some *text*", fields.Description, "the callers fields must not be modified")
+		})
+	}
+}
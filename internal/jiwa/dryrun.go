@@ -0,0 +1,64 @@
+package jiwa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// dryRunResponse is what calls that were skipped because of DryRun return.
+var dryRunResponse = []byte("{}")
+
+// dryRunKey stands in for the key of the n-th issue a dry run would have
+// created in project, so callers still have something to print.
+func dryRunKey(project string, n int) string {
+	return fmt.Sprintf("%s-DRYRUN%d", project, n)
+}
+
+func (c *Client) dryRunOutput() io.Writer {
+	if c.DryRunOutput == nil {
+		return os.Stderr
+	}
+
+	return c.DryRunOutput
+}
+
+// printDryRun writes the request that would have been sent, bulk commands
+// call this from several goroutines so every request is written at once.
+func (c *Client) printDryRun(method, api, endpoint string, params url.Values, body []byte) {
+	var b strings.Builder
+	b.WriteString(method + " " + api + "/" + endpoint)
+	if len(params) != 0 {
+		b.WriteString("?" + params.Encode())
+	}
+	b.WriteString("\n")
+
+	if len(body) != 0 {
+		var indented bytes.Buffer
+		if json.Indent(&indented, body, "", "  ") == nil {
+			b.Write(indented.Bytes())
+		} else {
+			b.Write(body)
+		}
+		b.WriteString("\n")
+	}
+
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+	fmt.Fprint(c.dryRunOutput(), b.String())
+}
+
+// dryRunf adds a note on how a request was planned to the dry run output.
+func (c *Client) dryRunf(format string, args ...interface{}) {
+	if !c.DryRun {
+		return
+	}
+
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+	fmt.Fprintf(c.dryRunOutput(), "# "+format+"\n", args...)
+}
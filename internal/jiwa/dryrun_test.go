@@ -0,0 +1,67 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_DryRun(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/JIWA-1/transitions", r.URL.Path)
+
+		json.NewEncoder(w).Encode(struct {
+			Transitions []jira.Transition `json:"transitions"`
+		}{Transitions: []jira.Transition{
+			{ID: "11", Name: "Start Progress", To: jira.Status{Name: "In Progress"}},
+			{ID: "31", Name: "Done", To: jira.Status{Name: "Done"}},
+		}})
+	})
+	var out bytes.Buffer
+	client.DryRun = true
+	client.DryRunOutput = &out
+
+	err := client.TransitionIssue(context.Background(), "JIWA-1", "In Progress")
+	assert.NoError(t, err)
+	assert.Equal(t, `# JIWA-1: transition "in progress" resolved to id 11
+POST rest/api/2/issue/JIWA-1/transitions
+{
+  "update": {},
+  "transition": {
+    "id": "11"
+  },
+  "fields": {}
+}
+`, out.String())
+}
+
+func TestClient_DryRunCreate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	client.DryRun = true
+	client.DryRunOutput = io.Discard
+
+	issue, err := client.CreateIssue(context.Background(), CreateIssueInput{Project: "JIWA", Summary: "one"})
+	assert.NoError(t, err)
+	assert.Equal(t, "JIWA-DRYRUN1", issue.Key)
+
+	results, err := client.CreateIssues(context.Background(), []CreateIssueInput{
+		{Project: "JIWA", Summary: "one"},
+		{Project: "JIWA", Summary: "two"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for i, r := range results {
+		assert.NoError(t, r.Err)
+		assert.Equal(t, fmt.Sprintf("JIWA-DRYRUN%d", i+1), r.Issue.Key)
+	}
+}
@@ -0,0 +1,113 @@
+package jiwa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors to check an APIError against with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrPermission   = errors.New("permission denied")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// APIError is returned for every call that Jira answered with a non 2xx
+// status code.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Endpoint   string
+	// ErrorMessages holds the general messages Jira sends back.
+	ErrorMessages []string
+	// Errors maps field names to the validation error for that field.
+	Errors map[string]string
+	// Body is the raw response in case it wasn't Jira's error JSON.
+	Body string
+
+	// raw is the response for endpoints that send more than the usual
+	// error JSON.
+	raw []byte
+}
+
+// newAPIError parses Jira's error JSON out of body, if that fails the body
+// is kept around as is.
+func newAPIError(method, endpoint string, statusCode int, body []byte) *APIError {
+	apiErr := APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+		raw:        body,
+	}
+
+	var errResp struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+		Message       string            `json:"message"`
+	}
+	err := json.Unmarshal(body, &errResp)
+	if err != nil {
+		apiErr.Body = strings.TrimSpace(string(body))
+		return &apiErr
+	}
+
+	apiErr.ErrorMessages = errResp.ErrorMessages
+	if errResp.Message != "" {
+		apiErr.ErrorMessages = append(apiErr.ErrorMessages, errResp.Message)
+	}
+	apiErr.Errors = errResp.Errors
+
+	return &apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+
+	details := make([]string, 0, len(e.ErrorMessages)+len(e.Errors))
+	details = append(details, e.ErrorMessages...)
+	for _, field := range e.FieldNames() {
+		details = append(details, field+": "+e.Errors[field])
+	}
+	if len(details) == 0 && e.Body != "" {
+		details = append(details, e.Body)
+	}
+
+	if len(details) == 0 {
+		return msg
+	}
+
+	return msg + ": " + strings.Join(details, "; ")
+}
+
+// Is lets errors.Is match an APIError against the sentinel errors by its
+// status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrPermission:
+		return e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest
+	default:
+		return false
+	}
+}
+
+// FieldNames returns the fields with validation errors in sorted order.
+func (e *APIError) FieldNames() []string {
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
@@ -0,0 +1,77 @@
+package jiwa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_callAPIError(t *testing.T) {
+	testData := []struct {
+		Name         string
+		Status       int
+		Body         string
+		OutSentinel  error
+		OutMessages  []string
+		OutErrors    map[string]string
+		OutErrorText string
+	}{
+		{
+			Name:         "NotFound",
+			Status:       http.StatusNotFound,
+			Body:         `{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`,
+			OutSentinel:  ErrNotFound,
+			OutMessages:  []string{"Issue does not exist or you do not have permission to see it."},
+			OutErrors:    map[string]string{},
+			OutErrorText: "GET issue/JIWA-1: 404 Not Found: Issue does not exist or you do not have permission to see it.",
+		},
+		{
+			Name:         "FieldValidation",
+			Status:       http.StatusBadRequest,
+			Body:         `{"errorMessages":[],"errors":{"summary":"You must specify a summary of the issue.","components":"Component is required."}}`,
+			OutSentinel:  ErrValidation,
+			OutMessages:  []string{},
+			OutErrors:    map[string]string{"summary": "You must specify a summary of the issue.", "components": "Component is required."},
+			OutErrorText: "GET issue/JIWA-1: 400 Bad Request: components: Component is required.; summary: You must specify a summary of the issue.",
+		},
+		{
+			Name:         "UnauthorizedWithoutJSON",
+			Status:       http.StatusUnauthorized,
+			Body:         "<html>Unauthorized</html>",
+			OutSentinel:  ErrUnauthorized,
+			OutErrorText: "GET issue/JIWA-1: 401 Unauthorized: <html>Unauthorized</html>",
+		},
+		{
+			Name:         "Forbidden",
+			Status:       http.StatusForbidden,
+			Body:         `{"message":"Client must be authenticated to access this resource."}`,
+			OutSentinel:  ErrPermission,
+			OutMessages:  []string{"Client must be authenticated to access this resource."},
+			OutErrorText: "GET issue/JIWA-1: 403 Forbidden: Client must be authenticated to access this resource.",
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(td.Status)
+				w.Write([]byte(td.Body))
+			})
+
+			_, err := client.GetIssue(context.Background(), "JIWA-1")
+
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.True(t, errors.Is(err, td.OutSentinel))
+			assert.Equal(t, td.Status, apiErr.StatusCode)
+			assert.Equal(t, td.OutMessages, apiErr.ErrorMessages)
+			assert.Equal(t, td.OutErrors, apiErr.Errors)
+			assert.Equal(t, td.OutErrorText, apiErr.Error())
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// textareaField is the custom field type holding rich text, it needs ADF
+// just like the description with API version 3.
+const textareaField = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// ListFields returns the metadata of all system and custom fields.
+func (c *Client) ListFields(ctx context.Context) ([]jira.Field, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, "field", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fields: %w", err)
+	}
+
+	var fields []jira.Field
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return fields, nil
+}
+
+// ResolveField finds a field by its ID, its name or its JQL clause name.
+// Names are matched case insensitive and have to be unique.
+func ResolveField(fields []jira.Field, name string) (jira.Field, error) {
+	for _, f := range fields {
+		if f.ID == name {
+			return f, nil
+		}
+	}
+
+	matches := make([]jira.Field, 0, 1)
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			matches = append(matches, f)
+			continue
+		}
+
+		for _, clause := range f.ClauseNames {
+			if strings.EqualFold(clause, name) {
+				matches = append(matches, f)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return jira.Field{}, fmt.Errorf("could not find a field called %s", name)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return jira.Field{}, fmt.Errorf("there are several fields called %s, use one of their IDs instead: %s", name, strings.Join(ids, ", "))
+	}
+}
+
+// CoerceFieldValue turns the raw string value into what Jira expects for
+// the field based on its schema. Arrays are comma separated, cascading
+// selects take "parent > child" and anything starting with { or [ is sent
+// as raw JSON for the cases not covered here.
+// User fields take the user's identity, see UserIdentity.
+// An empty value clears the field.
+func (c *Client) CoerceFieldValue(ctx context.Context, field jira.Field, raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if raw[0] == '{' || raw[0] == '[' {
+		if !json.Valid([]byte(raw)) {
+			return nil, fmt.Errorf("value for %s looks like JSON but isn't valid", field.Name)
+		}
+		return json.RawMessage(raw), nil
+	}
+
+	if field.Schema.Type == "array" {
+		parts := strings.Split(raw, ",")
+		values := make([]interface{}, 0, len(parts))
+		for _, p := range parts {
+			v, err := c.coerceSingleValue(ctx, field, field.Schema.Items, strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+
+	return c.coerceSingleValue(ctx, field, field.Schema.Type, raw)
+}
+
+func (c *Client) coerceSingleValue(ctx context.Context, field jira.Field, schemaType, raw string) (interface{}, error) {
+	switch schemaType {
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number, got %q", field.Name, raw)
+		}
+		return n, nil
+	case "string":
+		if field.Schema.Custom == textareaField {
+			return c.richText(ctx, raw), nil
+		}
+		return raw, nil
+	case "option":
+		return map[string]string{"value": raw}, nil
+	case "option-with-child":
+		parent, child, found := strings.Cut(raw, ">")
+		value := map[string]interface{}{"value": strings.TrimSpace(parent)}
+		if found {
+			value["child"] = map[string]string{"value": strings.TrimSpace(child)}
+		}
+		return value, nil
+	case "user":
+		return c.userRef(ctx, raw), nil
+	case "priority", "resolution", "issuetype", "component", "version", "securitylevel":
+		return map[string]string{"name": raw}, nil
+	case "project":
+		return map[string]string{"key": raw}, nil
+	case "date":
+		_, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a date like 2006-01-02, got %q", field.Name, raw)
+		}
+		return raw, nil
+	case "datetime":
+		for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+			t, err := time.ParseInLocation(layout, raw, time.Local)
+			if err == nil {
+				return t.Format("2006-01-02T15:04:05.000-0700"), nil
+			}
+		}
+		return nil, fmt.Errorf("%s needs a date and time like \"2006-01-02 15:04\", got %q", field.Name, raw)
+	default:
+		return raw, nil
+	}
+}
+
+// SetFields updates the given fields of an issue, keyed by field ID.
+func (c *Client) SetFields(ctx context.Context, key string, fields map[string]interface{}) error {
+	body, err := json.Marshal(struct {
+		Fields map[string]interface{} `json:"fields"`
+	}{Fields: fields})
+	if err != nil {
+		return fmt.Errorf("failed to marshal fields: %w", err)
+	}
+
+	_, err = c.callAPI(ctx, http.MethodPut, "issue/"+key, nil, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to set fields on %s: %w", key, err)
+	}
+
+	return nil
+}
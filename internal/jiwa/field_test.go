@@ -0,0 +1,85 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFields = []jira.Field{
+	{ID: "summary", Name: "Summary", ClauseNames: []string{"summary"}, Schema: jira.FieldSchema{Type: "string"}},
+	{ID: "customfield_10042", Name: "Story Points", Custom: true, ClauseNames: []string{"cf[10042]", "Story Points"}, Schema: jira.FieldSchema{Type: "number"}},
+	{ID: "customfield_10050", Name: "Team", Custom: true, ClauseNames: []string{"cf[10050]"}, Schema: jira.FieldSchema{Type: "option"}},
+	{ID: "customfield_10051", Name: "Team", Custom: true, ClauseNames: []string{"cf[10051]"}, Schema: jira.FieldSchema{Type: "string"}},
+}
+
+func TestResolveField(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InName     string
+		OutID      string
+		OutErrored bool
+	}{
+		{Name: "ByID", InName: "customfield_10042", OutID: "customfield_10042"},
+		{Name: "ByName", InName: "story points", OutID: "customfield_10042"},
+		{Name: "ByClauseName", InName: "cf[10050]", OutID: "customfield_10050"},
+		{Name: "Ambiguous", InName: "Team", OutErrored: true},
+		{Name: "Unknown", InName: "Severity", OutErrored: true},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			field, err := ResolveField(testFields, td.InName)
+
+			assert.Equal(t, td.OutErrored, err != nil)
+			assert.Equal(t, td.OutID, field.ID)
+		})
+	}
+}
+
+func TestClient_CoerceFieldValue(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InSchema   jira.FieldSchema
+		InValue    string
+		OutJSON    string
+		OutErrored bool
+	}{
+		{Name: "Number", InSchema: jira.FieldSchema{Type: "number"}, InValue: "5", OutJSON: `5`},
+		{Name: "NotANumber", InSchema: jira.FieldSchema{Type: "number"}, InValue: "five", OutErrored: true},
+		{Name: "String", InSchema: jira.FieldSchema{Type: "string"}, InValue: "Platform", OutJSON: `"Platform"`},
+		{Name: "Option", InSchema: jira.FieldSchema{Type: "option"}, InValue: "Platform", OutJSON: `{"value":"Platform"}`},
+		{Name: "Cascading", InSchema: jira.FieldSchema{Type: "option-with-child"}, InValue: "EU > Berlin", OutJSON: `{"child":{"value":"Berlin"},"value":"EU"}`},
+		{Name: "User", InSchema: jira.FieldSchema{Type: "user"}, InValue: "bob", OutJSON: `{"name":"bob"}`},
+		{Name: "ArrayOfStrings", InSchema: jira.FieldSchema{Type: "array", Items: "string"}, InValue: "a, b", OutJSON: `["a","b"]`},
+		{Name: "ArrayOfOptions", InSchema: jira.FieldSchema{Type: "array", Items: "option"}, InValue: "a,b", OutJSON: `[{"value":"a"},{"value":"b"}]`},
+		{Name: "Date", InSchema: jira.FieldSchema{Type: "date"}, InValue: "2023-04-01", OutJSON: `"2023-04-01"`},
+		{Name: "InvalidDate", InSchema: jira.FieldSchema{Type: "date"}, InValue: "01.04.2023", OutErrored: true},
+		{Name: "RawJSON", InSchema: jira.FieldSchema{Type: "any"}, InValue: `{"id":"1"}`, OutJSON: `{"id":"1"}`},
+		{Name: "InvalidJSON", InSchema: jira.FieldSchema{Type: "any"}, InValue: `{"id"`, OutErrored: true},
+		{Name: "Empty", InSchema: jira.FieldSchema{Type: "number"}, InValue: "", OutJSON: `null`},
+	}
+
+	c := &Client{APIVersion: "2", DeploymentType: DeploymentServer}
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			value, err := c.CoerceFieldValue(context.Background(), jira.Field{Name: td.Name, Schema: td.InSchema}, td.InValue)
+
+			assert.Equal(t, td.OutErrored, err != nil)
+			if td.OutErrored {
+				return
+			}
+
+			b, err := json.Marshal(value)
+			assert.NoError(t, err)
+			assert.JSONEq(t, td.OutJSON, string(b))
+		})
+	}
+}
@@ -9,53 +9,277 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
 )
 
+// Client talks to the Jira REST API, it must not be copied after first use.
 type Client struct {
-	Username   string
-	Password   string
-	Token      string
-	BaseURL    string
+	// Auth authenticates the requests, if it is nil Username and Password
+	// are used for basic auth or Token as a bearer token.
+	Auth     Authenticator
+	Username string
+	Password string
+	Token    string
+	BaseURL  string
+	// APIVersion is the version of the core REST API, if empty it is
+	// picked based on the deployment type.
 	APIVersion string
-	HTTPClient *http.Client
+	// DeploymentType is one of the Deployment constants, if empty it is
+	// detected through serverInfo when needed.
+	DeploymentType string
+	HTTPClient     *http.Client
+	RetryPolicy    RetryPolicy
+	// Cache keeps responses to GET requests on disk if set.
+	Cache *Cache
+	// DryRun prints every request that would change something to
+	// DryRunOutput instead of sending it, reads are still sent.
+	DryRun bool
+	// DryRunOutput defaults to stderr.
+	DryRunOutput io.Writer
+	// Trace logs and archives every request sent if set.
+	Trace *Trace
+	// Authorized is called once with whether Jira accepted the credentials,
+	// on the first response that tells: a success or a 401 that renewing
+	// the credentials didn't fix. Cached responses tell nothing.
+	Authorized func(ok bool)
+
+	serverInfoMu  sync.Mutex
+	serverInfo    *ServerInfo
+	serverInfoErr error
+	dryRunMu      sync.Mutex
+	authorized    sync.Once
 }
 
+// agileAPI is the path of the Jira Software REST API that holds boards and
+// sprints.
+const agileAPI = "rest/agile/1.0"
+
+// callAPI calls an endpoint of the core REST API in the configured version.
 func (c *Client) callAPI(ctx context.Context, method, endpoint string, params url.Values, body io.Reader) ([]byte, error) {
-	reqURL := fmt.Sprintf("%s/rest/api/%s/%s?%s", c.BaseURL, c.APIVersion, endpoint, params.Encode())
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	return c.call(ctx, c.coreAPI(ctx), method, endpoint, params, body)
+}
+
+// callAgileAPI calls an endpoint of the Jira Software REST API.
+func (c *Client) callAgileAPI(ctx context.Context, method, endpoint string, params url.Values, body io.Reader) ([]byte, error) {
+	return c.call(ctx, agileAPI, method, endpoint, params, body)
+}
+
+// call sends a request to the endpoint of the REST API under api, like
+// "rest/api/2", and returns the response body.
+func (c *Client) call(ctx context.Context, api, method, endpoint string, params url.Values, body io.Reader) ([]byte, error) {
+	reqURL := c.url(api, endpoint, params)
+
+	// the body has to be replayable for retries
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	if c.DryRun && method != http.MethodGet {
+		c.printDryRun(method, api, endpoint, params, bodyBytes)
+		return dryRunResponse, nil
+	}
+
+	// the cache is best effort, failing to read or write it never fails
+	// the call
+	var identity string
+	var cached *cacheEntry
+	if c.Cache != nil {
+		identity = c.cacheIdentity()
+	}
+	if identity != "" && method == http.MethodGet && ctx.Value(latestKey{}) == nil {
+		cached = c.Cache.load(identity, endpointClass(endpoint), reqURL)
+		if cached != nil && cached.fresh(c.Cache.ttl(cached.Class)) {
+			return cached.Body, nil
+		}
+		if cached != nil && !cached.revalidatable() {
+			cached = nil
+		}
+	}
+
+	resp, respBytes, err := c.send(ctx, method, endpoint, false, func() (*http.Request, []byte, error) {
+		var bodyReader io.Reader
+		if bodyBytes != nil {
+			bodyReader = bytes.NewReader(bodyBytes)
+		}
+
+		req, err := c.newRequest(ctx, method, reqURL, bodyReader)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cached != nil {
+			cached.setConditional(req)
+		}
+		return req, bodyBytes, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	switch {
-	case c.Username != "" && c.Password != "":
-		req.SetBasicAuth(c.Username, c.Password)
-	case c.Token != "":
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	default:
-		return nil, errors.New("either username+password need to be set or token")
+	if resp.StatusCode == http.StatusNotModified {
+		if cached == nil {
+			return nil, newAPIError(method, endpoint, resp.StatusCode, respBytes)
+		}
+		cached.StoredAt = time.Now()
+		_ = c.Cache.store(identity, cached)
+		return cached.Body, nil
 	}
-	req.Header.Set("content-type", "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	c.updateCache(identity, method, reqURL, endpoint, resp.Header, respBytes)
+	return respBytes, nil
+}
+
+// send sends the requests built by newReq until one succeeds, the
+// credentials were renewed once after a 401 or the retry policy gives up.
+// newReq is called for every attempt and returns the request with the body
+// to trace. The body of the returned response is read into the returned
+// bytes, unless stream is set and the request succeeded.
+func (c *Client) send(ctx context.Context, method, endpoint string, stream bool, newReq func() (*http.Request, []byte, error)) (*http.Response, []byte, error) {
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		req, reqBody, err := newReq()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, respBytes, err := c.do(req, reqBody, stream)
+		var statusCode int
+		var header http.Header
+		if err == nil {
+			statusCode = resp.StatusCode
+			header = resp.Header
+		}
+		if err == nil && (statusCode <= 299 || statusCode == http.StatusNotModified) {
+			c.reportAuthorized(true)
+			return resp, respBytes, nil
+		}
+
+		// expired sessions and tokens get one chance to be renewed
+		if refresher, ok := c.Auth.(Refresher); ok && statusCode == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			refreshErr := refresher.Refresh(ctx)
+			if refreshErr != nil {
+				return nil, nil, refreshErr
+			}
+			attempt--
+			continue
+		}
+
+		if attempt < c.RetryPolicy.MaxAttempts && c.RetryPolicy.shouldRetry(method, statusCode, err) {
+			delay, ok := c.RetryPolicy.delay(attempt, header)
+			if ok {
+				sleepErr := sleep(ctx, delay)
+				if sleepErr != nil {
+					return nil, nil, sleepErr
+				}
+				continue
+			}
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if statusCode == http.StatusUnauthorized {
+			c.reportAuthorized(false)
+		}
+		return nil, nil, newAPIError(method, endpoint, statusCode, respBytes)
+	}
+}
+
+func (c *Client) reportAuthorized(ok bool) {
+	if c.Authorized != nil {
+		c.authorized.Do(func() { c.Authorized(ok) })
+	}
+}
+
+// updateCache stores the response to a GET request, any other request
+// changed something in Jira and invalidates the cached issues.
+func (c *Client) updateCache(identity, method, reqURL, endpoint string, header http.Header, body []byte) {
+	if identity == "" {
+		return
+	}
+
+	if method != http.MethodGet {
+		_ = c.Cache.invalidate(identity)
+		return
+	}
+
+	_ = c.Cache.store(identity, &cacheEntry{
+		URL:          reqURL,
+		Class:        endpointClass(endpoint),
+		StoredAt:     time.Now(),
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Body:         body,
+	})
+}
+
+// coreAPI returns the path of the core REST API.
+func (c *Client) coreAPI(ctx context.Context) string {
+	return "rest/api/" + c.apiVersion(ctx)
+}
+
+// url builds the full URL of an endpoint of the REST API under api.
+func (c *Client) url(api, endpoint string, params url.Values) string {
+	return fmt.Sprintf("%s/%s/%s?%s", c.BaseURL, api, endpoint, params.Encode())
+}
+
+// newRequest builds an authenticated JSON request, body may be nil.
+func (c *Client) newRequest(ctx context.Context, method, reqURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	auth, err := c.authenticator()
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("failed to call API %d: %s", resp.StatusCode, string(bodyBytes))
+	err = auth.Authenticate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
 	}
+	req.Header.Set("content-type", "application/json")
 
-	return bodyBytes, nil
+	return req, nil
+}
+
+// do sends the request and reads the full response, or leaves the body of
+// a successful response unread if stream is set. The error is only set if
+// no response could be read at all.
+func (c *Client) do(req *http.Request, reqBody []byte, stream bool) (*http.Response, []byte, error) {
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		c.Trace.add(start, req, reqBody, nil, nil, err)
+		return nil, nil, err
+	}
+
+	if stream && resp.StatusCode <= 299 {
+		c.Trace.add(start, req, reqBody, resp, nil, nil)
+		return resp, nil, nil
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	c.Trace.add(start, req, reqBody, resp, respBytes, err)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, respBytes, nil
 }
 
 type CreateIssueInput struct {
@@ -66,22 +290,18 @@ type CreateIssueInput struct {
 	Component   string
 	Assignee    string
 	Type        string
+	// Parent is the key of the issue a sub-task is created under, Type
+	// needs to be a sub-task type for this to work.
+	Parent string
+	// Fields holds additional fields keyed by their ID, see ResolveField
+	// and CoerceFieldValue to build them from human readable input.
+	Fields map[string]interface{}
 }
 
 // CreateIssue tries to create the issue in the target project
 // if the creation was successful it returns the issue ID
 func (c *Client) CreateIssue(ctx context.Context, input CreateIssueInput) (jira.Issue, error) {
-	i := jira.Issue{
-		Fields: &jira.IssueFields{
-			Project:     jira.Project{Key: input.Project},
-			Summary:     input.Summary,
-			Description: input.Description,
-			Type:        jira.IssueType{Name: input.Type},
-			Labels:      input.Labels,
-		},
-	}
-
-	bodyBytes, err := json.Marshal(i)
+	bodyBytes, err := json.Marshal(c.newIssue(ctx, input))
 	if err != nil {
 		return jira.Issue{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
@@ -90,6 +310,9 @@ func (c *Client) CreateIssue(ctx context.Context, input CreateIssueInput) (jira.
 	if err != nil {
 		return jira.Issue{}, fmt.Errorf("failed to create issue: %w", err)
 	}
+	if c.DryRun {
+		return jira.Issue{Key: dryRunKey(input.Project, 1)}, nil
+	}
 
 	var j jira.Issue
 	err = json.Unmarshal(b, &j)
@@ -100,23 +323,118 @@ func (c *Client) CreateIssue(ctx context.Context, input CreateIssueInput) (jira.
 	return j, nil
 }
 
+// newIssue builds the issue Jira is sent to create it.
+func (c *Client) newIssue(ctx context.Context, input CreateIssueInput) jira.Issue {
+	fields := &jira.IssueFields{
+		Project:     jira.Project{Key: input.Project},
+		Summary:     input.Summary,
+		Description: input.Description,
+		Type:        jira.IssueType{Name: input.Type},
+		Labels:      input.Labels,
+	}
+	if input.Parent != "" {
+		fields.Parent = &jira.Parent{Key: input.Parent}
+	}
+	if input.Component != "" {
+		fields.Components = []*jira.Component{{Name: input.Component}}
+	}
+	if len(input.Fields) != 0 {
+		fields.Unknowns = tcontainer.NewMarshalMap()
+		for id, v := range input.Fields {
+			fields.Unknowns[id] = v
+		}
+	}
+
+	return jira.Issue{
+		Fields: c.encodeRichTextFields(ctx, fields),
+	}
+}
+
 // GetIssue finds an issue based on its key
 func (c *Client) GetIssue(ctx context.Context, key string) (jira.Issue, error) {
+	j, _, err := c.getIssue(ctx, key)
+	return j, err
+}
+
+func (c *Client) getIssue(ctx context.Context, key string) (jira.Issue, []byte, error) {
 	b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key, nil, nil)
 	if err != nil {
-		return jira.Issue{}, fmt.Errorf("failed to get issue: %w", err)
+		return jira.Issue{}, nil, fmt.Errorf("failed to get issue: %w", err)
 	}
 
 	var j jira.Issue
-	err = json.Unmarshal(b, &j)
+	err = c.unmarshalIssue(ctx, b, &j)
 	if err != nil {
-		return jira.Issue{}, fmt.Errorf("failed to unmarshal response: %w", err)
+		return jira.Issue{}, nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return j, nil
+	return j, b, nil
+}
+
+type latestKey struct{}
+
+// GetLatestIssue is GetIssue without the cache, for when a stale issue would
+// do harm, like restoring it later. The response still updates the cache.
+func (c *Client) GetLatestIssue(ctx context.Context, key string) (jira.Issue, error) {
+	return c.GetIssue(context.WithValue(ctx, latestKey{}, true), key)
+}
+
+// GetLatestIssueDescription is GetLatestIssue that also returns the
+// description the way Jira sent it, on version 3 that's the ADF document
+// the issue's description is flattened from.
+func (c *Client) GetLatestIssueDescription(ctx context.Context, key string) (jira.Issue, json.RawMessage, error) {
+	j, b, err := c.getIssue(context.WithValue(ctx, latestKey{}, true), key)
+	if err != nil {
+		return jira.Issue{}, nil, err
+	}
+
+	var raw struct {
+		Fields struct {
+			Description json.RawMessage `json:"description"`
+		} `json:"fields"`
+	}
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return jira.Issue{}, nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	description := raw.Fields.Description
+	if description == nil {
+		description = json.RawMessage("null")
+	}
+
+	return j, description, nil
+}
+
+// ListSubtasks returns the sub-tasks of an issue.
+func (c *Client) ListSubtasks(ctx context.Context, key string) ([]jira.Subtasks, error) {
+	params := url.Values{}
+	params.Set("fields", "subtasks")
+
+	b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sub-tasks of %s: %w", key, err)
+	}
+
+	var issue jira.Issue
+	err = json.Unmarshal(b, &issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	subtasks := make([]jira.Subtasks, 0)
+	if issue.Fields == nil {
+		return subtasks, nil
+	}
+	for _, st := range issue.Fields.Subtasks {
+		subtasks = append(subtasks, *st)
+	}
+
+	return subtasks, nil
 }
 
 func (c *Client) UpdateIssue(ctx context.Context, issue jira.Issue) error {
+	issue.Fields = c.encodeRichTextFields(ctx, issue.Fields)
 	body, err := json.Marshal(issue)
 	if err != nil {
 		return fmt.Errorf("failed to marshal input issue: %w", err)
@@ -130,43 +448,213 @@ func (c *Client) UpdateIssue(ctx context.Context, issue jira.Issue) error {
 	return nil
 }
 
-func (c *Client) AssignIssue(ctx context.Context, key string, assignee string) error {
-	i := jira.Issue{
-		Key: key,
-		Fields: &jira.IssueFields{
-			Assignee: &jira.User{Name: assignee},
-		},
+// SetSummaryAndDescription replaces both fields of the issue, unlike
+// UpdateIssue an empty description clears it.
+func (c *Client) SetSummaryAndDescription(ctx context.Context, key, summary, description string) error {
+	var desc interface{}
+	if description != "" {
+		desc = c.richText(ctx, description)
 	}
 
-	return c.UpdateIssue(ctx, i)
+	return c.SetFields(ctx, key, map[string]interface{}{
+		"summary":     summary,
+		"description": desc,
+	})
 }
 
-func (c *Client) Search(ctx context.Context, jql string) ([]jira.Issue, error) {
-	if jql == "" {
-		return nil, errors.New("cannot search with empty search query")
+// AssignIssue assigns the issue to the user, use UserIdentity to get the
+// identity of a jira.User. An empty identity unassigns the issue.
+func (c *Client) AssignIssue(ctx context.Context, key string, identity string) error {
+	body, err := json.Marshal(c.userRef(ctx, identity))
+	if err != nil {
+		return fmt.Errorf("failed to marshal assignee: %w", err)
 	}
 
-	params := url.Values{}
-	params.Set("jql", jql)
+	_, err = c.callAPI(ctx, http.MethodPut, "issue/"+key+"/assignee", nil, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to assign %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// SearchOptions controls how many issues a search returns and how many
+// are requested from Jira per call.
+type SearchOptions struct {
+	// Limit caps the total amount of issues returned, 0 means no limit.
+	Limit int
+	// PageSize is the maxResults value sent per call, 0 leaves it up to
+	// the server.
+	PageSize int
+	// Fields are the fields returned for each issue, if empty it's the
+	// navigable fields.
+	Fields []string
+}
+
+type searchResponse struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	Issues     []json.RawMessage `json:"issues"`
+}
+
+// searchJQLResponse is the response of Cloud's search/jql endpoint which
+// pages with a token instead of an offset.
+type searchJQLResponse struct {
+	NextPageToken string            `json:"nextPageToken"`
+	IsLast        bool              `json:"isLast"`
+	Issues        []json.RawMessage `json:"issues"`
+}
+
+// searchPager fetches the next page of a search, last is set once there
+// are no more pages.
+type searchPager func(ctx context.Context, pageSize int) (issues []json.RawMessage, last bool, err error)
+
+// Search pages through all results of the JQL query and returns them once
+// the last page has been read or opts.Limit is reached.
+func (c *Client) Search(ctx context.Context, jql string, opts SearchOptions) ([]jira.Issue, error) {
+	issueChan, errChan := c.SearchStream(ctx, jql, opts)
+
+	issues := make([]jira.Issue, 0)
+	for issue := range issueChan {
+		issues = append(issues, issue)
+	}
 
-	b, err := c.callAPI(ctx, http.MethodGet, "search", params, nil)
+	err := <-errChan
 	if err != nil {
 		return nil, err
 	}
 
-	searchResp := struct {
-		StartAt    int          `json:"startAt"`
-		MaxResults int          `json:"maxResults"`
-		Total      int          `json:"total"`
-		Issues     []jira.Issue `json:"issues"`
-	}{}
-	err = json.Unmarshal(b, &searchResp)
-	if err != nil {
-		fmt.Println(string(b))
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	return issues, nil
+}
+
+// SearchStream works like Search but hands out issues as soon as their
+// page arrives. The issue channel is closed once the search is done, after
+// that the error channel yields at most one error.
+// Cancel the context to stop reading pages early.
+func (c *Client) SearchStream(ctx context.Context, jql string, opts SearchOptions) (<-chan jira.Issue, <-chan error) {
+	issueChan := make(chan jira.Issue)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(errChan)
+		defer close(issueChan)
+
+		if jql == "" {
+			errChan <- errors.New("cannot search with empty search query")
+			return
+		}
+
+		fields := "*navigable"
+		if len(opts.Fields) != 0 {
+			fields = strings.Join(opts.Fields, ",")
+		}
+
+		nextPage := c.offsetSearch(jql, fields)
+		if c.IsCloud(ctx) {
+			nextPage = c.tokenSearch(jql, fields)
+		}
+
+		sent := 0
+		for {
+			pageSize := opts.PageSize
+			if opts.Limit > 0 && (pageSize == 0 || opts.Limit-sent < pageSize) {
+				pageSize = opts.Limit - sent
+			}
+
+			page, last, err := nextPage(ctx, pageSize)
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			for _, raw := range page {
+				var issue jira.Issue
+				err = c.unmarshalIssue(ctx, raw, &issue)
+				if err != nil {
+					errChan <- fmt.Errorf("failed to unmarshal issue: %w", err)
+					return
+				}
+
+				select {
+				case issueChan <- issue:
+				case <-ctx.Done():
+					errChan <- ctx.Err()
+					return
+				}
+
+				sent++
+				if opts.Limit > 0 && sent >= opts.Limit {
+					return
+				}
+			}
+
+			if last || len(page) == 0 {
+				return
+			}
+		}
+	}()
+
+	return issueChan, errChan
+}
+
+// offsetSearch pages through the search endpoint with startAt.
+func (c *Client) offsetSearch(jql, fields string) searchPager {
+	startAt := 0
+	return func(ctx context.Context, pageSize int) ([]json.RawMessage, bool, error) {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", fields)
+		params.Set("startAt", strconv.Itoa(startAt))
+		if pageSize > 0 {
+			params.Set("maxResults", strconv.Itoa(pageSize))
+		}
+
+		b, err := c.callAPI(ctx, http.MethodGet, "search", params, nil)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var page searchResponse
+		err = json.Unmarshal(b, &page)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		startAt += len(page.Issues)
+		return page.Issues, startAt >= page.Total, nil
 	}
+}
 
-	return searchResp.Issues, nil
+// tokenSearch pages through Cloud's search/jql endpoint, which only
+// returns issue IDs unless asked for the fields.
+func (c *Client) tokenSearch(jql, fields string) searchPager {
+	token := ""
+	return func(ctx context.Context, pageSize int) ([]json.RawMessage, bool, error) {
+		params := url.Values{}
+		params.Set("jql", jql)
+		params.Set("fields", fields)
+		if token != "" {
+			params.Set("nextPageToken", token)
+		}
+		if pageSize > 0 {
+			params.Set("maxResults", strconv.Itoa(pageSize))
+		}
+
+		b, err := c.callAPI(ctx, http.MethodGet, "search/jql", params, nil)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var page searchJQLResponse
+		err = json.Unmarshal(b, &page)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		token = page.NextPageToken
+		return page.Issues, page.IsLast || token == "", nil
+	}
 }
 
 func (c *Client) LabelIssue(ctx context.Context, key string, labels ...string) error {
@@ -216,6 +704,8 @@ func (c *Client) TransitionIssue(ctx context.Context, key string, status string)
 	status = strings.ToLower(status)
 
 	validTransitions := make([]string, len(transitions), len(transitions))
+	// transitions are usually named after the status they lead to, if
+	// none is the one leading there is taken
 	transitionID := ""
 	for _, t := range transitions {
 		if strings.ToLower(t.Name) == status {
@@ -224,6 +714,11 @@ func (c *Client) TransitionIssue(ctx context.Context, key string, status string)
 
 		validTransitions = append(validTransitions, t.Name)
 	}
+	for _, t := range transitions {
+		if transitionID == "" && strings.ToLower(t.To.Name) == status {
+			transitionID = t.ID
+		}
+	}
 
 	if transitionID == "" {
 		return fmt.Errorf(
@@ -234,6 +729,8 @@ func (c *Client) TransitionIssue(ctx context.Context, key string, status string)
 		)
 	}
 
+	c.dryRunf("%s: transition %q resolved to id %s", key, status, transitionID)
+
 	tr := jira.CreateTransitionPayload{
 		Transition: jira.TransitionPayload{ID: transitionID},
 	}
@@ -265,20 +762,39 @@ func (c *Client) GetProject(ctx context.Context, key string) (jira.Project, erro
 	return result, nil
 }
 
-func (c *Client) CommentOnIssue(ctx context.Context, issueID string, comment string) error {
+func (c *Client) CommentOnIssue(ctx context.Context, issueID string, comment string) (jira.Comment, error) {
 	bodyStruct := struct {
-		Body string `json:"body"`
+		Body interface{} `json:"body"`
 	}{
-		Body: comment,
+		Body: c.richText(ctx, comment),
 	}
 	body, err := json.Marshal(&bodyStruct)
 	if err != nil {
-		return fmt.Errorf("failed to marshal comment body: %w", err)
+		return jira.Comment{}, fmt.Errorf("failed to marshal comment body: %w", err)
+	}
+
+	b, err := c.callAPI(ctx, http.MethodPost, "issue/"+issueID+"/comment", nil, bytes.NewBuffer(body))
+	if err != nil {
+		return jira.Comment{}, fmt.Errorf("failed comment on issue %s: %w", issueID, err)
+	}
+
+	// the body is ADF on API version 3, only the ID is of interest here
+	var created struct {
+		ID string `json:"id"`
+	}
+	err = json.Unmarshal(b, &created)
+	if err != nil {
+		return jira.Comment{}, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	_, err = c.callAPI(ctx, http.MethodPost, "issue/"+issueID+"/comment", nil, bytes.NewBuffer(body))
+	return jira.Comment{ID: created.ID, Body: comment}, nil
+}
+
+// DeleteComment removes the comment from the issue.
+func (c *Client) DeleteComment(ctx context.Context, issueID, commentID string) error {
+	_, err := c.callAPI(ctx, http.MethodDelete, "issue/"+issueID+"/comment/"+commentID, nil, nil)
 	if err != nil {
-		return fmt.Errorf("failed comment on issue %s: %w", issueID, err)
+		return fmt.Errorf("failed to delete comment %s of %s: %w", commentID, issueID, err)
 	}
 
 	return nil
@@ -0,0 +1,132 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestClient spins up a httptest server with handler and points a
+// client with basic auth credentials at it.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &Client{
+		Username:       "user",
+		Password:       "pass",
+		BaseURL:        server.URL,
+		APIVersion:     "2",
+		DeploymentType: DeploymentServer,
+		HTTPClient:     server.Client(),
+	}
+}
+
+// pagedSearchHandler serves total issues in pages of at most maxPage
+// issues, ignoring larger maxResults values like Jira does.
+func pagedSearchHandler(t *testing.T, total, maxPage int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/search", r.URL.Path)
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		if err != nil || maxResults > maxPage {
+			maxResults = maxPage
+		}
+
+		resp := struct {
+			StartAt    int          `json:"startAt"`
+			MaxResults int          `json:"maxResults"`
+			Total      int          `json:"total"`
+			Issues     []jira.Issue `json:"issues"`
+		}{StartAt: startAt, MaxResults: maxResults, Total: total}
+		for i := startAt; i < total && i < startAt+maxResults; i++ {
+			resp.Issues = append(resp.Issues, jira.Issue{Key: fmt.Sprintf("JIWA-%d", i+1)})
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestClient_Search(t *testing.T) {
+	testData := []struct {
+		Name      string
+		Total     int
+		MaxPage   int
+		InOptions SearchOptions
+		OutCount  int
+	}{
+		{
+			Name:     "SinglePage",
+			Total:    10,
+			MaxPage:  50,
+			OutCount: 10,
+		},
+		{
+			Name:     "MultiplePages",
+			Total:    120,
+			MaxPage:  50,
+			OutCount: 120,
+		},
+		{
+			Name:      "PageSizeSmallerThanServerMax",
+			Total:     25,
+			MaxPage:   50,
+			InOptions: SearchOptions{PageSize: 10},
+			OutCount:  25,
+		},
+		{
+			Name:      "LimitAcrossPages",
+			Total:     120,
+			MaxPage:   50,
+			InOptions: SearchOptions{Limit: 75},
+			OutCount:  75,
+		},
+		{
+			Name:      "LimitLargerThanTotal",
+			Total:     20,
+			MaxPage:   50,
+			InOptions: SearchOptions{Limit: 75, PageSize: 5},
+			OutCount:  20,
+		},
+		{
+			Name:     "NoResults",
+			Total:    0,
+			MaxPage:  50,
+			OutCount: 0,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, pagedSearchHandler(t, td.Total, td.MaxPage))
+
+			issues, err := client.Search(context.Background(), "project=JIWA", td.InOptions)
+			assert.NoError(t, err)
+			assert.Len(t, issues, td.OutCount)
+			for i, issue := range issues {
+				assert.Equal(t, fmt.Sprintf("JIWA-%d", i+1), issue.Key)
+			}
+		})
+	}
+}
+
+func TestClient_SearchEmptyQuery(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected for an empty query")
+	})
+
+	_, err := client.Search(context.Background(), "", SearchOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ListIssueLinkTypes returns all link types configured on the instance.
+func (c *Client) ListIssueLinkTypes(ctx context.Context) ([]jira.IssueLinkType, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, "issueLinkType", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue link types: %w", err)
+	}
+
+	var resp struct {
+		IssueLinkTypes []jira.IssueLinkType `json:"issueLinkTypes"`
+	}
+	err = json.Unmarshal(b, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resp.IssueLinkTypes, nil
+}
+
+// LinkIssues creates a link of the given type so that from relates to to
+// by the outward description of the type, "from blocks to" for example.
+// Jira's API names the issues the other way around which is why from ends
+// up as the inwardIssue.
+func (c *Client) LinkIssues(ctx context.Context, linkType, from, to string) error {
+	type linkTypeName struct {
+		Name string `json:"name"`
+	}
+	link := struct {
+		Type         linkTypeName `json:"type"`
+		InwardIssue  jira.Issue   `json:"inwardIssue"`
+		OutwardIssue jira.Issue   `json:"outwardIssue"`
+	}{
+		Type:         linkTypeName{Name: linkType},
+		InwardIssue:  jira.Issue{Key: from},
+		OutwardIssue: jira.Issue{Key: to},
+	}
+
+	body, err := json.Marshal(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal link: %w", err)
+	}
+
+	_, err = c.callAPI(ctx, http.MethodPost, "issueLink", nil, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", from, to, err)
+	}
+
+	return nil
+}
+
+// ListIssueLinks returns the links of an issue in both directions.
+func (c *Client) ListIssueLinks(ctx context.Context, key string) ([]jira.IssueLink, error) {
+	params := url.Values{}
+	params.Set("fields", "issuelinks")
+
+	b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links of %s: %w", key, err)
+	}
+
+	var issue jira.Issue
+	err = json.Unmarshal(b, &issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	links := make([]jira.IssueLink, 0)
+	if issue.Fields == nil {
+		return links, nil
+	}
+	for _, l := range issue.Fields.IssueLinks {
+		links = append(links, *l)
+	}
+
+	return links, nil
+}
+
+func (c *Client) DeleteIssueLink(ctx context.Context, id string) error {
+	_, err := c.callAPI(ctx, http.MethodDelete, "issueLink/"+id, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete issue link %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package jiwa
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_LinkIssues(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issueLink", r.URL.Path)
+
+		// the issue the link starts from is the inward one in Jira's API
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"type":{"name":"Blocks"},"inwardIssue":{"key":"JIWA-1"},"outwardIssue":{"key":"JIWA-2"}}`, string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	err := client.LinkIssues(context.Background(), "Blocks", "JIWA-1", "JIWA-2")
+	assert.NoError(t, err)
+}
+
+func TestClient_ListIssueLinks(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/JIWA-1", r.URL.Path)
+		assert.Equal(t, "issuelinks", r.URL.Query().Get("fields"))
+
+		w.Write([]byte(`{"key":"JIWA-1","fields":{"issuelinks":[{"id":"10001","type":{"name":"Blocks"},"outwardIssue":{"key":"JIWA-2"}}]}}`))
+	})
+
+	links, err := client.ListIssueLinks(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, "10001", links[0].ID)
+		assert.Equal(t, "JIWA-2", links[0].OutwardIssue.Key)
+	}
+}
+
+func TestClient_DeleteIssueLink(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InStatus   int
+		OutErrored bool
+	}{
+		{Name: "Deleted", InStatus: http.StatusNoContent},
+		{Name: "NotFound", InStatus: http.StatusNotFound, OutErrored: true},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/rest/api/2/issueLink/10001", r.URL.Path)
+
+				body, _ := io.ReadAll(r.Body)
+				assert.Empty(t, body)
+				w.WriteHeader(td.InStatus)
+			})
+
+			err := client.DeleteIssueLink(context.Background(), "10001")
+			assert.Equal(t, td.OutErrored, err != nil)
+		})
+	}
+}
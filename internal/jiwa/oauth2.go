@@ -0,0 +1,407 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints of Atlassian's OAuth 2.0 (3LO) implementation.
+const (
+	OAuth2AuthURL      = "https://auth.atlassian.com/authorize"
+	OAuth2TokenURL     = "https://auth.atlassian.com/oauth/token"
+	OAuth2ResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	// OAuth2APIURL is where the API of a Cloud site is reached with an
+	// OAuth 2.0 token, the cloud ID of the site has to be appended.
+	OAuth2APIURL = "https://api.atlassian.com/ex/jira/"
+)
+
+// DefaultOAuth2Scopes are the scopes jiwa needs, offline_access is what
+// gets us a refresh token.
+var DefaultOAuth2Scopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// OAuth2Token is what OAuth2 keeps in its token file.
+type OAuth2Token struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	Expiry       time.Time `json:"expiry"`
+	// CloudID identifies the site the token was issued for.
+	CloudID string `json:"cloudId,omitempty"`
+	// AccountID is the user the token was issued to.
+	AccountID string `json:"accountId,omitempty"`
+}
+
+// expired reports whether the token needs to be refreshed, leaving some
+// slack so it doesn't expire while a request is underway.
+func (t OAuth2Token) expired() bool {
+	return t.Expiry.IsZero() || time.Now().Add(time.Minute).After(t.Expiry)
+}
+
+// TokenStore loads and saves OAuth 2.0 tokens.
+type TokenStore interface {
+	Load() (OAuth2Token, error)
+	Save(OAuth2Token) error
+}
+
+// FileTokenStore keeps the token as JSON in a file only the user can read.
+type FileTokenStore struct {
+	Path string
+}
+
+func (s FileTokenStore) Load() (OAuth2Token, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return OAuth2Token{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var token OAuth2Token
+	err = json.Unmarshal(b, &token)
+	if err != nil {
+		return OAuth2Token{}, fmt.Errorf("failed to unmarshal token file: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s FileTokenStore) Save(token OAuth2Token) error {
+	b, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.Path), 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	err = os.WriteFile(s.Path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	return nil
+}
+
+// OAuth2 authenticates with an OAuth 2.0 (3LO) access token, refreshing it
+// through the refresh token when it expires and saving the new tokens.
+type OAuth2 struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Store        TokenStore
+	HTTPClient   *http.Client
+	// TokenURL defaults to OAuth2TokenURL.
+	TokenURL string
+	// APIURL defaults to OAuth2APIURL.
+	APIURL string
+
+	mu    sync.Mutex
+	token *OAuth2Token
+}
+
+func (a *OAuth2) Authenticate(ctx context.Context, req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		token, err := a.Store.Load()
+		if err != nil {
+			return fmt.Errorf("no OAuth 2.0 token, log in first: %w", err)
+		}
+		a.token = &token
+	}
+
+	if a.token.expired() {
+		err := a.refresh(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+a.token.AccessToken)
+	return nil
+}
+
+func (a *OAuth2) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		token, err := a.Store.Load()
+		if err != nil {
+			return fmt.Errorf("no OAuth 2.0 token, log in first: %w", err)
+		}
+		a.token = &token
+	}
+
+	return a.refresh(ctx)
+}
+
+// refresh has to be called with mu held.
+func (a *OAuth2) refresh(ctx context.Context) error {
+	if a.token.RefreshToken == "" {
+		return errors.New("the OAuth 2.0 token expired and can't be refreshed, log in again")
+	}
+
+	token, err := a.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": a.token.RefreshToken,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh OAuth 2.0 token: %w", err)
+	}
+
+	token.CloudID = a.token.CloudID
+	token.AccountID = a.token.AccountID
+	// Atlassian rotates refresh tokens but keep the old one if none came back
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	a.token = &token
+
+	return a.Store.Save(token)
+}
+
+// AuthCodeURL returns the URL the user has to open to grant jiwa access,
+// state is handed back to the redirect URL unchanged.
+func (a *OAuth2) AuthCodeURL(state string) string {
+	scopes := a.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultOAuth2Scopes
+	}
+
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", a.ClientID)
+	params.Set("scope", strings.Join(scopes, " "))
+	params.Set("redirect_uri", a.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+
+	return OAuth2AuthURL + "?" + params.Encode()
+}
+
+// Exchange trades the code from the redirect for tokens and saves them.
+func (a *OAuth2) Exchange(ctx context.Context, code string) (OAuth2Token, error) {
+	token, err := a.requestToken(ctx, map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": a.RedirectURL,
+	})
+	if err != nil {
+		return OAuth2Token{}, fmt.Errorf("failed to exchange code for OAuth 2.0 token: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = &token
+
+	return token, a.Store.Save(token)
+}
+
+// SetCloudID records the site the token belongs to and saves it.
+func (a *OAuth2) SetCloudID(cloudID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		return errors.New("no OAuth 2.0 token, log in first")
+	}
+	a.token.CloudID = cloudID
+
+	return a.Store.Save(*a.token)
+}
+
+// LookUpAccount records the user the token was issued to and saves it,
+// the token has to know its site already.
+func (a *OAuth2) LookUpAccount(ctx context.Context) error {
+	a.mu.Lock()
+	if a.token == nil {
+		token, err := a.Store.Load()
+		if err == nil {
+			a.token = &token
+		}
+	}
+	cloudID := ""
+	if a.token != nil {
+		cloudID = a.token.CloudID
+	}
+	a.mu.Unlock()
+
+	if cloudID == "" {
+		return errors.New("no OAuth 2.0 token for a site, log in first")
+	}
+
+	apiURL := a.APIURL
+	if apiURL == "" {
+		apiURL = OAuth2APIURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+cloudID+"/rest/api/2/myself", nil)
+	if err != nil {
+		return err
+	}
+
+	err = a.Authenticate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to look up account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("failed to look up account: %w", newAPIError(http.MethodGet, "myself", resp.StatusCode, b))
+	}
+
+	var me struct {
+		AccountID string `json:"accountId"`
+	}
+	err = json.Unmarshal(b, &me)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token.AccountID = me.AccountID
+
+	return a.Store.Save(*a.token)
+}
+
+// owner identifies the user on the site the token belongs to, it is empty
+// for tokens saved before the account was recorded.
+func (a *OAuth2) owner() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		token, err := a.Store.Load()
+		if err != nil {
+			return ""
+		}
+		a.token = &token
+	}
+
+	if a.token.CloudID == "" || a.token.AccountID == "" {
+		return ""
+	}
+
+	return a.token.CloudID + "/" + a.token.AccountID
+}
+
+// AccessibleResource is a site the OAuth 2.0 token grants access to.
+type AccessibleResource struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// AccessibleResources lists the sites the current token grants access to.
+func (a *OAuth2) AccessibleResources(ctx context.Context) ([]AccessibleResource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, OAuth2ResourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.Authenticate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accessible resources: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to list accessible resources: %w", newAPIError(http.MethodGet, "accessible-resources", resp.StatusCode, b))
+	}
+
+	var resources []AccessibleResource
+	err = json.Unmarshal(b, &resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resources, nil
+}
+
+func (a *OAuth2) requestToken(ctx context.Context, grant map[string]string) (OAuth2Token, error) {
+	grant["client_id"] = a.ClientID
+	grant["client_secret"] = a.ClientSecret
+
+	body, err := json.Marshal(grant)
+	if err != nil {
+		return OAuth2Token{}, err
+	}
+
+	tokenURL := a.TokenURL
+	if tokenURL == "" {
+		tokenURL = OAuth2TokenURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewBuffer(body))
+	if err != nil {
+		return OAuth2Token{}, err
+	}
+	req.Header.Set("content-type", "application/json")
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return OAuth2Token{}, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OAuth2Token{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode > 299 {
+		return OAuth2Token{}, newAPIError(http.MethodPost, "oauth/token", resp.StatusCode, b)
+	}
+
+	var tokenResp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	err = json.Unmarshal(b, &tokenResp)
+	if err != nil {
+		return OAuth2Token{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return OAuth2Token{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package jiwa
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how callAPI retries requests that failed because of
+// rate limiting, an overloaded server or a broken connection.
+// The zero value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total amount of attempts including the first one.
+	MaxAttempts int `json:"maxAttempts"`
+	// BaseDelay is the delay before the first retry, it doubles on every
+	// following attempt.
+	BaseDelay time.Duration `json:"baseDelay"`
+	// MaxDelay caps the exponential backoff. If the server asks us to wait
+	// longer than this via its headers we give up instead.
+	MaxDelay time.Duration `json:"maxDelay"`
+}
+
+// isIdempotent reports whether sending the request twice has the same
+// effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry decides if a request should be sent again based on the
+// outcome of the last attempt. Non idempotent requests are only retried on
+// a 429 since Jira rejects those before doing any work.
+func (p RetryPolicy) shouldRetry(method string, statusCode int, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return isIdempotent(method)
+	}
+
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// delay returns how long to wait before the given retry attempt, counting
+// from 1. Retry-After and the X-RateLimit-* headers take precedence over
+// the exponential backoff, if they ask for more than MaxDelay the returned
+// bool is false.
+func (p RetryPolicy) delay(attempt int, header http.Header) (time.Duration, bool) {
+	if d, ok := serverDelay(header); ok {
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			return 0, false
+		}
+		return d, true
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	// equal jitter, keeps at least half of the backoff so we don't
+	// hammer the server with instant retries
+	if d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+
+	return d, true
+}
+
+// serverDelay reads the wait time the server asked for, Jira Cloud sends
+// Retry-After in seconds and X-RateLimit-Reset as an ISO 8601 timestamp.
+func serverDelay(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+
+		at, err := http.ParseTime(retryAfter)
+		if err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		reset := header.Get("X-RateLimit-Reset")
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+			at, err := time.Parse(layout, reset)
+			if err == nil {
+				return max(time.Until(at), 0), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,159 @@
+package jiwa
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_callAPIRetries(t *testing.T) {
+	testData := []struct {
+		Name         string
+		Method       string
+		FailStatus   int
+		Failures     int
+		OutCalls     int32
+		OutSucceeded bool
+	}{
+		{
+			Name:         "GetRetriedOnServiceUnavailable",
+			Method:       http.MethodGet,
+			FailStatus:   http.StatusServiceUnavailable,
+			Failures:     2,
+			OutCalls:     3,
+			OutSucceeded: true,
+		},
+		{
+			Name:         "PostRetriedOnTooManyRequests",
+			Method:       http.MethodPost,
+			FailStatus:   http.StatusTooManyRequests,
+			Failures:     1,
+			OutCalls:     2,
+			OutSucceeded: true,
+		},
+		{
+			Name:         "PostNotRetriedOnServiceUnavailable",
+			Method:       http.MethodPost,
+			FailStatus:   http.StatusServiceUnavailable,
+			Failures:     1,
+			OutCalls:     1,
+			OutSucceeded: false,
+		},
+		{
+			Name:         "ClientErrorsAreNotRetried",
+			Method:       http.MethodGet,
+			FailStatus:   http.StatusBadRequest,
+			Failures:     1,
+			OutCalls:     1,
+			OutSucceeded: false,
+		},
+		{
+			Name:         "GivesUpAfterMaxAttempts",
+			Method:       http.MethodGet,
+			FailStatus:   http.StatusTooManyRequests,
+			Failures:     5,
+			OutCalls:     3,
+			OutSucceeded: false,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&calls, 1)) <= td.Failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(td.FailStatus)
+					return
+				}
+				w.Write([]byte("{}"))
+			})
+			client.RetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+			_, err := client.callAPI(context.Background(), td.Method, "issue", nil, nil)
+			assert.Equal(t, td.OutSucceeded, err == nil)
+			assert.Equal(t, td.OutCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	testData := []struct {
+		Name     string
+		Attempt  int
+		Header   http.Header
+		OutMin   time.Duration
+		OutMax   time.Duration
+		OutRetry bool
+	}{
+		{
+			Name:     "FirstBackoff",
+			Attempt:  1,
+			OutMin:   50 * time.Millisecond,
+			OutMax:   100 * time.Millisecond,
+			OutRetry: true,
+		},
+		{
+			Name:     "BackoffDoubles",
+			Attempt:  3,
+			OutMin:   200 * time.Millisecond,
+			OutMax:   400 * time.Millisecond,
+			OutRetry: true,
+		},
+		{
+			Name:     "BackoffCappedAtMaxDelay",
+			Attempt:  10,
+			OutMin:   500 * time.Millisecond,
+			OutMax:   time.Second,
+			OutRetry: true,
+		},
+		{
+			Name:     "RetryAfterSeconds",
+			Attempt:  1,
+			Header:   http.Header{"Retry-After": []string{"1"}},
+			OutMin:   time.Second,
+			OutMax:   time.Second,
+			OutRetry: true,
+		},
+		{
+			Name:     "RetryAfterTooLong",
+			Attempt:  1,
+			Header:   http.Header{"Retry-After": []string{"120"}},
+			OutRetry: false,
+		},
+		{
+			Name:    "RateLimitResetInThePast",
+			Attempt: 1,
+			Header: http.Header{
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{"2021-05-10T11:00Z"},
+			},
+			OutMin:   0,
+			OutMax:   0,
+			OutRetry: true,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			d, ok := policy.delay(td.Attempt, td.Header)
+
+			assert.Equal(t, td.OutRetry, ok)
+			if ok {
+				assert.GreaterOrEqual(t, d, td.OutMin)
+				assert.LessOrEqual(t, d, td.OutMax)
+			}
+		})
+	}
+}
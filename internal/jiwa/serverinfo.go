@@ -0,0 +1,106 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Deployment types as reported by serverInfo.
+const (
+	DeploymentCloud      = "Cloud"
+	DeploymentServer     = "Server"
+	DeploymentDataCenter = "DataCenter"
+)
+
+// ServerInfo describes the Jira instance the client talks to.
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	VersionNumbers []int  `json:"versionNumbers"`
+	DeploymentType string `json:"deploymentType"`
+	BuildNumber    int    `json:"buildNumber"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// IsCloud tells whether the instance is Jira Cloud.
+func (s ServerInfo) IsCloud() bool {
+	return strings.EqualFold(s.DeploymentType, DeploymentCloud)
+}
+
+// ServerInfo fetches the information about the Jira instance, it is only
+// asked for once per client. A failure is remembered as well so callers
+// falling back to a guess don't ask again for every issue they handle.
+func (c *Client) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	c.serverInfoMu.Lock()
+	defer c.serverInfoMu.Unlock()
+
+	if c.serverInfo != nil {
+		return *c.serverInfo, nil
+	}
+	if c.serverInfoErr != nil {
+		return ServerInfo{}, c.serverInfoErr
+	}
+
+	// serverInfo is the same in every API version, asking version 2
+	// avoids needing to know the version in the first place
+	b, err := c.call(ctx, "rest/api/2", http.MethodGet, "serverInfo", nil, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to get server info: %w", err)
+		// a cancelled call says nothing about the server
+		if ctx.Err() == nil {
+			c.serverInfoErr = err
+		}
+		return ServerInfo{}, err
+	}
+
+	var info ServerInfo
+	err = json.Unmarshal(b, &info)
+	if err != nil {
+		c.serverInfoErr = fmt.Errorf("failed to unmarshal server info: %w", err)
+		return ServerInfo{}, c.serverInfoErr
+	}
+
+	c.serverInfo = &info
+	return info, nil
+}
+
+// IsCloud tells whether the client talks to Jira Cloud, which identifies
+// users by accountId instead of their name and has its own search
+// endpoint. A configured DeploymentType saves asking the server, if asking
+// fails the host name is used to guess.
+func (c *Client) IsCloud(ctx context.Context) bool {
+	if c.DeploymentType != "" {
+		return strings.EqualFold(c.DeploymentType, DeploymentCloud)
+	}
+
+	info, err := c.ServerInfo(ctx)
+	if err == nil {
+		return info.IsCloud()
+	}
+
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	return strings.HasSuffix(host, ".atlassian.net") || strings.HasSuffix(host, ".jira.com")
+}
+
+// apiVersion returns the configured API version, without one it picks 3
+// for Cloud and 2 for Server and Data Center which don't offer 3 at all.
+func (c *Client) apiVersion(ctx context.Context) string {
+	if c.APIVersion != "" {
+		return c.APIVersion
+	}
+
+	if c.IsCloud(ctx) {
+		return "3"
+	}
+
+	return "2"
+}
@@ -0,0 +1,102 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ServerInfo(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/serverInfo", r.URL.Path)
+		calls++
+		w.Write([]byte(`{"baseUrl":"https://catouc.atlassian.net","version":"1001.0.0-SNAPSHOT","deploymentType":"Cloud","buildNumber":100231}`))
+	})
+	client.APIVersion = ""
+	client.DeploymentType = ""
+
+	assert.True(t, client.IsCloud(context.Background()))
+	assert.Equal(t, "3", client.apiVersion(context.Background()))
+
+	info, err := client.ServerInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 100231, info.BuildNumber)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_ServerInfoFailure(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/serverInfo", r.URL.Path)
+		calls++
+		w.WriteHeader(http.StatusForbidden)
+	})
+	client.APIVersion = ""
+	client.DeploymentType = ""
+
+	// every issue of a search asks, the server only once
+	for i := 0; i < 3; i++ {
+		assert.False(t, client.IsCloud(context.Background()))
+		assert.Equal(t, "2", client.apiVersion(context.Background()))
+	}
+
+	_, err := client.ServerInfo(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_apiVersion(t *testing.T) {
+	testData := []struct {
+		Name             string
+		InAPIVersion     string
+		InDeploymentType string
+		OutVersion       string
+	}{
+		{Name: "Configured", InAPIVersion: "2", InDeploymentType: DeploymentCloud, OutVersion: "2"},
+		{Name: "Cloud", InDeploymentType: DeploymentCloud, OutVersion: "3"},
+		{Name: "Server", InDeploymentType: DeploymentServer, OutVersion: "2"},
+		{Name: "DataCenter", InDeploymentType: DeploymentDataCenter, OutVersion: "2"},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			c := &Client{APIVersion: td.InAPIVersion, DeploymentType: td.InDeploymentType}
+
+			assert.Equal(t, td.OutVersion, c.apiVersion(context.Background()))
+		})
+	}
+}
+
+func TestClient_SearchCloud(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/search/jql", r.URL.Path)
+		assert.Equal(t, "*navigable", r.URL.Query().Get("fields"))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("nextPageToken"))
+		resp := searchJQLResponse{IsLast: page == 2}
+		if !resp.IsLast {
+			resp.NextPageToken = strconv.Itoa(page + 1)
+		}
+		for i := 0; i < 50; i++ {
+			b, _ := json.Marshal(jira.Issue{Key: fmt.Sprintf("JIWA-%d", page*50+i+1)})
+			resp.Issues = append(resp.Issues, b)
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	})
+	client.DeploymentType = DeploymentCloud
+
+	issues, err := client.Search(context.Background(), "project = JIWA", SearchOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 150)
+	assert.Equal(t, "JIWA-150", issues[149].Key)
+}
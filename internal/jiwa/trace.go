@@ -0,0 +1,462 @@
+package jiwa
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLoggedBody is how much of a body Trace logs, the HAR archive always
+// holds all of it.
+const maxLoggedBody = 4096
+
+// redactedHeaders never show up in the log or the archive, only the scheme
+// of Authorization is kept.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// redactedFields are the keys of JSON bodies whose values never show up in
+// the log or the archive, like the password of a session login or the
+// secrets of an OAuth 2.0 grant.
+var redactedFields = map[string]bool{
+	"password":      true,
+	"client_secret": true,
+	"refresh_token": true,
+	"access_token":  true,
+	"code_verifier": true,
+}
+
+// Trace records every request the client sends, for when something has to
+// be debugged with the Jira admins. It is safe for concurrent use.
+type Trace struct {
+	// Log gets every request and response with its timing if set.
+	Log io.Writer
+	// HARFile gets a HAR 1.2 archive of all requests if set. Every request
+	// is added to the end of the file, so the archive is complete however
+	// jiwa exits.
+	HARFile string
+
+	mu  sync.Mutex
+	har *os.File
+	// harEnd is where the closing brackets of the archive start, the next
+	// entry is written there.
+	harEnd     int64
+	harEntries int
+	harErr     error
+}
+
+// WriteHAR creates HARFile with an empty archive, the requests traced
+// afterwards are added to it.
+func (t *Trace) WriteHAR() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.openHAR()
+}
+
+// Close closes HARFile, the archive is complete already.
+func (t *Trace) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.har == nil {
+		return nil
+	}
+	err := t.har.Close()
+	t.har = nil
+	return err
+}
+
+// harIndent is the indentation of the entries in the archive.
+const harIndent = "      "
+
+func (t *Trace) openHAR() error {
+	archive := har{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "jiwa", Version: version()},
+		Entries: []harEntry{},
+	}}
+
+	b, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal HAR archive: %w", err)
+	}
+
+	f, err := os.Create(t.HARFile)
+	if err != nil {
+		return fmt.Errorf("failed to write HAR archive: %w", err)
+	}
+
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write HAR archive: %w", err)
+	}
+
+	if t.har != nil {
+		t.har.Close()
+	}
+	t.har = f
+	t.harEnd = int64(bytes.LastIndex(b, []byte("[]")) + 1)
+	t.harEntries = 0
+	return nil
+}
+
+// appendHAR writes entry over the closing brackets of the archive and
+// closes it again, so the file stays valid without rewriting it.
+func (t *Trace) appendHAR(entry harEntry) error {
+	if t.har == nil {
+		err := t.openHAR()
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(entry, harIndent, "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal HAR entry: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if t.harEntries > 0 {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\n" + harIndent)
+	buf.Write(b)
+	end := t.harEnd + int64(buf.Len())
+	buf.WriteString("\n    ]\n  }\n}\n")
+
+	_, err = t.har.WriteAt(buf.Bytes(), t.harEnd)
+	if err != nil {
+		return fmt.Errorf("failed to write HAR archive: %w", err)
+	}
+
+	t.harEnd = end
+	t.harEntries++
+	return nil
+}
+
+// add traces a request, resp is nil and err set if no response was read.
+func (t *Trace) add(start time.Time, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error) {
+	if t == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	reqBody = redactBody(reqBody)
+	respBody = redactBody(respBody)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Log != nil {
+		fmt.Fprint(t.Log, logExchange(req, reqBody, resp, respBody, elapsed, err))
+	}
+
+	// the archive stops being updated after the first failure instead
+	// of reporting it with every request
+	if t.HARFile == "" || t.harErr != nil {
+		return
+	}
+
+	t.harErr = t.appendHAR(newHAREntry(start, elapsed, req, reqBody, resp, respBody, err))
+	if t.harErr != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", t.harErr)
+	}
+}
+
+// logExchange formats the request and response like curl -v does.
+func logExchange(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, elapsed time.Duration, err error) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "> %s %s\n", req.Method, req.URL)
+	logHeaders(&b, ">", req.Header)
+	logBody(&b, ">", reqBody)
+
+	if resp == nil {
+		fmt.Fprintf(&b, "< failed after %s: %s\n", elapsed.Round(time.Millisecond), err)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "< %s in %s\n", resp.Status, elapsed.Round(time.Millisecond))
+	logHeaders(&b, "<", resp.Header)
+	logBody(&b, "<", respBody)
+	if err != nil {
+		fmt.Fprintf(&b, "< failed to read body: %s\n", err)
+	}
+
+	return b.String()
+}
+
+func logHeaders(b *strings.Builder, prefix string, header http.Header) {
+	for _, h := range redactHeaders(header) {
+		fmt.Fprintf(b, "%s %s: %s\n", prefix, h.Name, h.Value)
+	}
+}
+
+func logBody(b *strings.Builder, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	text := string(body)
+	if len(body) > maxLoggedBody {
+		text = fmt.Sprintf("%s... (%d bytes)", body[:maxLoggedBody], len(body))
+	}
+
+	b.WriteString(prefix + "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		b.WriteString(prefix + " " + line + "\n")
+	}
+}
+
+// redactHeaders returns the headers sorted by name with the secrets
+// replaced.
+func redactHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]harNameValue, 0, len(names))
+	for _, name := range names {
+		for _, value := range header[name] {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] {
+				value = redact(name, value)
+			}
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return headers
+}
+
+func redact(name, value string) string {
+	if http.CanonicalHeaderKey(name) == "Authorization" || http.CanonicalHeaderKey(name) == "Proxy-Authorization" {
+		scheme, _, found := strings.Cut(value, " ")
+		if found {
+			return scheme + " [REDACTED]"
+		}
+	}
+
+	return "[REDACTED]"
+}
+
+// redactBody replaces the values of redactedFields anywhere in a JSON body,
+// bodies without them are returned as they are.
+func redactBody(body []byte) []byte {
+	if len(body) == 0 || !utf8.Valid(body) {
+		return body
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if d.Decode(&v) != nil || !redactValue(v) {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// redactValue reports whether anything in v was redacted.
+func redactValue(v interface{}) bool {
+	redacted := false
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && redactedFields[key] {
+				v[key] = "[REDACTED]"
+				redacted = true
+				continue
+			}
+			redacted = redactValue(value) || redacted
+		}
+		// authorization codes are only secret in token requests, "code"
+		// means something else everywhere else
+		if _, ok := v["grant_type"]; ok {
+			if _, ok := v["code"].(string); ok {
+				v["code"] = "[REDACTED]"
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			redacted = redactValue(value) || redacted
+		}
+	}
+
+	return redacted
+}
+
+func version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+
+	return info.Main.Version
+}
+
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Error is set if no response was read, custom fields start with an
+	// underscore.
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(start time.Time, elapsed time.Duration, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error) harEntry {
+	ms := float64(elapsed.Microseconds()) / 1000
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []struct{}{},
+			Headers:     redactHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Cookies:     []struct{}{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		// the time to first byte isn't known, so it's all waiting
+		Timings: harTimings{Wait: ms},
+	}
+
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range query[name] {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	if len(reqBody) != 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     string(reqBody),
+		}
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if resp == nil {
+		return entry
+	}
+
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+	entry.Response.HTTPVersion = resp.Proto
+	entry.Response.Headers = redactHeaders(resp.Header)
+	entry.Response.BodySize = len(respBody)
+	entry.Response.Content = harContent{
+		Size:     len(respBody),
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     string(respBody),
+	}
+	if !utf8.Valid(respBody) {
+		entry.Response.Content.Text = base64.StdEncoding.EncodeToString(respBody)
+		entry.Response.Content.Encoding = "base64"
+	}
+
+	return entry
+}
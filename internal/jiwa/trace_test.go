@@ -0,0 +1,111 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Trace(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "JSESSIONID=secret")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"10001"}`))
+	})
+	var log bytes.Buffer
+	harFile := filepath.Join(t.TempDir(), "jiwa.har")
+	client.Trace = &Trace{Log: &log, HARFile: harFile}
+	t.Cleanup(func() { client.Trace.Close() })
+
+	_, err := client.callAPI(context.Background(), http.MethodPost, "issue/JIWA-1/comment", nil, bytes.NewBufferString(`{"body":"hi"}`))
+	assert.NoError(t, err)
+
+	assert.Contains(t, log.String(), "> POST "+client.BaseURL+"/rest/api/2/issue/JIWA-1/comment")
+	assert.Contains(t, log.String(), "> Authorization: Basic [REDACTED]\n")
+	assert.Contains(t, log.String(), "> {\"body\":\"hi\"}\n")
+	assert.Contains(t, log.String(), "< 201 Created in ")
+	assert.Contains(t, log.String(), "< Set-Cookie: [REDACTED]\n")
+	assert.Contains(t, log.String(), "< {\"id\":\"10001\"}\n")
+
+	b, err := os.ReadFile(harFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret")
+	assert.NotContains(t, string(b), "dXNlcjpwYXNz")
+
+	var archive har
+	assert.NoError(t, json.Unmarshal(b, &archive))
+	assert.Equal(t, "1.2", archive.Log.Version)
+	if assert.Len(t, archive.Log.Entries, 1) {
+		entry := archive.Log.Entries[0]
+		assert.Equal(t, http.MethodPost, entry.Request.Method)
+		assert.Equal(t, `{"body":"hi"}`, entry.Request.PostData.Text)
+		assert.Equal(t, http.StatusCreated, entry.Response.Status)
+		assert.Equal(t, "Created", entry.Response.StatusText)
+		assert.Equal(t, `{"id":"10001"}`, entry.Response.Content.Text)
+	}
+
+	// later requests are added to the archive
+	_, err = client.callAPI(context.Background(), http.MethodPost, "issue/JIWA-2/comment", nil, bytes.NewBufferString(`{"body":"ho"}`))
+	assert.NoError(t, err)
+
+	b, err = os.ReadFile(harFile)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(b, &archive))
+	if assert.Len(t, archive.Log.Entries, 2) {
+		assert.Equal(t, `{"body":"ho"}`, archive.Log.Entries[1].Request.PostData.Text)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	testData := []struct {
+		Name string
+		In   string
+		Out  string
+	}{
+		{
+			Name: "SessionLogin",
+			In:   `{"username":"user","password":"hunter2"}`,
+			Out:  `{"password":"[REDACTED]","username":"user"}`,
+		},
+		{
+			Name: "OAuth2Refresh",
+			In:   `{"client_id":"id","client_secret":"secret","grant_type":"refresh_token","refresh_token":"refresh"}`,
+			Out:  `{"client_id":"id","client_secret":"[REDACTED]","grant_type":"refresh_token","refresh_token":"[REDACTED]"}`,
+		},
+		{
+			Name: "OAuth2Exchange",
+			In:   `{"code":"abc","grant_type":"authorization_code"}`,
+			Out:  `{"code":"[REDACTED]","grant_type":"authorization_code"}`,
+		},
+		{
+			Name: "OAuth2Token",
+			In:   `{"access_token":"access","expires_in":3600,"refresh_token":"refresh"}`,
+			Out:  `{"access_token":"[REDACTED]","expires_in":3600,"refresh_token":"[REDACTED]"}`,
+		},
+		{
+			Name: "CodeOutsideOfGrants",
+			In:   `{"values": [{"code": "JIWA"}]}`,
+			Out:  `{"values": [{"code": "JIWA"}]}`,
+		},
+		{
+			Name: "NotJSON",
+			In:   `password=hunter2`,
+			Out:  `password=hunter2`,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.Out, string(redactBody([]byte(td.In))))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// AmbiguousUserError is returned when a query matches more than one user
+// and none of them exactly.
+type AmbiguousUserError struct {
+	Query string
+	Users []jira.User
+}
+
+func (e *AmbiguousUserError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d users, use one of:", e.Query, len(e.Users))
+	for _, u := range e.Users {
+		fmt.Fprintf(&b, "\n  %s", describeUser(u))
+	}
+
+	return b.String()
+}
+
+func describeUser(u jira.User) string {
+	id := u.Name
+	if id == "" {
+		id = u.AccountID
+	}
+
+	details := u.DisplayName
+	if u.EmailAddress != "" {
+		details += ", " + u.EmailAddress
+	}
+
+	return id + " (" + details + ")"
+}
+
+// UserIdentity returns what identifies the user in API calls, the
+// accountId on Cloud and the user name on Server and Data Center.
+func (c *Client) UserIdentity(ctx context.Context, u jira.User) string {
+	if c.IsCloud(ctx) {
+		return u.AccountID
+	}
+
+	return u.Name
+}
+
+// userRef builds the user object Jira expects in request bodies, an empty
+// user turns into a null reference.
+func (c *Client) userRef(ctx context.Context, identity string) map[string]interface{} {
+	field := "name"
+	if c.IsCloud(ctx) {
+		field = "accountId"
+	}
+
+	if identity == "" {
+		return map[string]interface{}{field: nil}
+	}
+
+	return map[string]interface{}{field: identity}
+}
+
+// SearchUsers finds users by email, display name or user name.
+func (c *Client) SearchUsers(ctx context.Context, query string) ([]jira.User, error) {
+	return c.searchUsers(ctx, "user/search", c.userQuery(ctx, query))
+}
+
+// SearchAssignableUsers is like SearchUsers but only returns users the
+// issue can be assigned to.
+func (c *Client) SearchAssignableUsers(ctx context.Context, issueKey, query string) ([]jira.User, error) {
+	params := c.userQuery(ctx, query)
+	params.Set("issueKey", issueKey)
+
+	return c.searchUsers(ctx, "user/assignable/search", params)
+}
+
+func (c *Client) userQuery(ctx context.Context, query string) url.Values {
+	params := url.Values{}
+	if c.IsCloud(ctx) {
+		params.Set("query", query)
+	} else {
+		params.Set("username", query)
+	}
+
+	return params
+}
+
+func (c *Client) searchUsers(ctx context.Context, endpoint string, params url.Values) ([]jira.User, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	var users []jira.User
+	err = json.Unmarshal(b, &users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return users, nil
+}
+
+// ResolveUser finds the one user the query stands for, if issueKey is set
+// only users assignable to that issue are considered. Exact matches on the
+// accountId, name, email or display name win over partial ones.
+func (c *Client) ResolveUser(ctx context.Context, issueKey, query string) (jira.User, error) {
+	var users []jira.User
+	var err error
+	if issueKey != "" {
+		users, err = c.SearchAssignableUsers(ctx, issueKey, query)
+	} else {
+		users, err = c.SearchUsers(ctx, query)
+	}
+	if err != nil {
+		return jira.User{}, err
+	}
+
+	exact := make([]jira.User, 0, 1)
+	for _, u := range users {
+		if userMatches(u, query) {
+			exact = append(exact, u)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) > 1:
+		return jira.User{}, &AmbiguousUserError{Query: query, Users: exact}
+	case len(users) == 1:
+		return users[0], nil
+	case len(users) > 1:
+		return jira.User{}, &AmbiguousUserError{Query: query, Users: users}
+	}
+
+	return jira.User{}, fmt.Errorf("no user matches %q: %w", query, ErrNotFound)
+}
+
+func userMatches(u jira.User, query string) bool {
+	for _, candidate := range []string{u.AccountID, u.Name, u.Key, u.EmailAddress, u.DisplayName} {
+		if candidate != "" && strings.EqualFold(candidate, query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Myself returns the user the client is authenticated as.
+func (c *Client) Myself(ctx context.Context) (jira.User, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, "myself", nil, nil)
+	if err != nil {
+		return jira.User{}, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	var u jira.User
+	err = json.Unmarshal(b, &u)
+	if err != nil {
+		return jira.User{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return u, nil
+}
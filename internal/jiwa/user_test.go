@@ -0,0 +1,127 @@
+package jiwa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ResolveUser(t *testing.T) {
+	testData := []struct {
+		Name        string
+		InQuery     string
+		InUsers     []jira.User
+		OutName     string
+		OutAmbigous bool
+		OutNotFound bool
+	}{
+		{
+			Name:    "SingleResult",
+			InQuery: "bob",
+			InUsers: []jira.User{{Name: "bsmith", DisplayName: "Bob Smith"}},
+			OutName: "bsmith",
+		},
+		{
+			Name:    "ExactMatchWins",
+			InQuery: "bob@example.com",
+			InUsers: []jira.User{
+				{Name: "bob", EmailAddress: "bob@example.com"},
+				{Name: "bobby", EmailAddress: "bob@example.com.au"},
+			},
+			OutName: "bob",
+		},
+		{
+			Name:    "Ambiguous",
+			InQuery: "bob",
+			InUsers: []jira.User{
+				{Name: "bsmith", DisplayName: "Bob Smith"},
+				{Name: "bjones", DisplayName: "Bob Jones"},
+			},
+			OutAmbigous: true,
+		},
+		{
+			Name:        "NoMatch",
+			InQuery:     "alice",
+			InUsers:     []jira.User{},
+			OutNotFound: true,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/api/2/user/assignable/search", r.URL.Path)
+				assert.Equal(t, "JIWA-1", r.URL.Query().Get("issueKey"))
+				assert.Equal(t, td.InQuery, r.URL.Query().Get("username"))
+				json.NewEncoder(w).Encode(td.InUsers)
+			})
+
+			user, err := client.ResolveUser(context.Background(), "JIWA-1", td.InQuery)
+
+			var ambiguousErr *AmbiguousUserError
+			assert.Equal(t, td.OutAmbigous, errors.As(err, &ambiguousErr))
+			assert.Equal(t, td.OutNotFound, errors.Is(err, ErrNotFound))
+			assert.Equal(t, td.OutName, user.Name)
+		})
+	}
+}
+
+func TestClient_AssignIssue(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InIdentity string
+		OutBody    string
+	}{
+		{Name: "Assign", InIdentity: "bob", OutBody: `{"name":"bob"}`},
+		{Name: "Unassign", InIdentity: "", OutBody: `{"name":null}`},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/api/2/issue/JIWA-1/assignee", r.URL.Path)
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, td.OutBody, string(body))
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			err := client.AssignIssue(context.Background(), "JIWA-1", td.InIdentity)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_userRef(t *testing.T) {
+	testData := []struct {
+		Name             string
+		InDeploymentType string
+		InIdentity       string
+		OutJSON          string
+	}{
+		{Name: "Server", InDeploymentType: DeploymentServer, InIdentity: "bob", OutJSON: `{"name":"bob"}`},
+		{Name: "Cloud", InDeploymentType: DeploymentCloud, InIdentity: "5b10ac8d82e05b22cc7d4ef5", OutJSON: `{"accountId":"5b10ac8d82e05b22cc7d4ef5"}`},
+		{Name: "CloudEmpty", InDeploymentType: DeploymentCloud, InIdentity: "", OutJSON: `{"accountId":null}`},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			c := &Client{DeploymentType: td.InDeploymentType}
+
+			b, err := json.Marshal(c.userRef(context.Background(), td.InIdentity))
+			assert.NoError(t, err)
+			assert.JSONEq(t, td.OutJSON, string(b))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func (c *Client) ListWatchers(ctx context.Context, key string) ([]jira.User, error) {
+	b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key+"/watchers", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchers of %s: %w", key, err)
+	}
+
+	var resp struct {
+		Watchers []jira.User `json:"watchers"`
+	}
+	err = json.Unmarshal(b, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return resp.Watchers, nil
+}
+
+// AddWatcher puts the user on the watch list of the issue, identity is
+// what UserIdentity returns for the user.
+func (c *Client) AddWatcher(ctx context.Context, key, identity string) error {
+	// the endpoint takes the bare user name or accountId as a JSON string
+	body, err := json.Marshal(identity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watcher: %w", err)
+	}
+
+	_, err = c.callAPI(ctx, http.MethodPost, "issue/"+key+"/watchers", nil, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to add %s as watcher to %s: %w", identity, key, err)
+	}
+
+	return nil
+}
+
+func (c *Client) RemoveWatcher(ctx context.Context, key, identity string) error {
+	params := url.Values{}
+	if c.IsCloud(ctx) {
+		params.Set("accountId", identity)
+	} else {
+		params.Set("username", identity)
+	}
+
+	_, err := c.callAPI(ctx, http.MethodDelete, "issue/"+key+"/watchers", params, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove %s as watcher from %s: %w", identity, key, err)
+	}
+
+	return nil
+}
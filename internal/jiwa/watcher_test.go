@@ -0,0 +1,85 @@
+package jiwa
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ListWatchers(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/JIWA-1/watchers", r.URL.Path)
+		w.Write([]byte(`{"watchCount":2,"watchers":[{"name":"alice"},{"name":"bob"}]}`))
+	})
+
+	watchers, err := client.ListWatchers(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	if assert.Len(t, watchers, 2) {
+		assert.Equal(t, "alice", watchers[0].Name)
+		assert.Equal(t, "bob", watchers[1].Name)
+	}
+}
+
+func TestClient_AddWatcher(t *testing.T) {
+	testData := []struct {
+		Name             string
+		InDeploymentType string
+		InIdentity       string
+		OutBody          string
+	}{
+		{Name: "ServerSendsName", InDeploymentType: DeploymentServer, InIdentity: "alice", OutBody: `"alice"`},
+		{Name: "CloudSendsAccountID", InDeploymentType: DeploymentCloud, InIdentity: "5b10ac8d82e05b22cc7d4ef5", OutBody: `"5b10ac8d82e05b22cc7d4ef5"`},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/rest/api/2/issue/JIWA-1/watchers", r.URL.Path)
+
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, td.OutBody, string(body))
+				w.WriteHeader(http.StatusNoContent)
+			})
+			client.DeploymentType = td.InDeploymentType
+
+			err := client.AddWatcher(context.Background(), "JIWA-1", td.InIdentity)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_RemoveWatcher(t *testing.T) {
+	testData := []struct {
+		Name             string
+		InDeploymentType string
+		InIdentity       string
+		OutQuery         string
+	}{
+		{Name: "ServerUsesUsername", InDeploymentType: DeploymentServer, InIdentity: "alice", OutQuery: "username=alice"},
+		{Name: "CloudUsesAccountID", InDeploymentType: DeploymentCloud, InIdentity: "5b10ac8d82e05b22cc7d4ef5", OutQuery: "accountId=5b10ac8d82e05b22cc7d4ef5"},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/rest/api/2/issue/JIWA-1/watchers", r.URL.Path)
+				assert.Equal(t, td.OutQuery, r.URL.RawQuery)
+				w.WriteHeader(http.StatusNoContent)
+			})
+			client.DeploymentType = td.InDeploymentType
+
+			err := client.RemoveWatcher(context.Background(), "JIWA-1", td.InIdentity)
+			assert.NoError(t, err)
+		})
+	}
+}
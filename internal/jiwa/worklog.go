@@ -0,0 +1,157 @@
+package jiwa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+var timeSpentRegEx = regexp.MustCompile(`^\s*(\d+(\.\d+)?\s*[wdhm]\s*)+$`)
+var timeSpentPartRegEx = regexp.MustCompile(`\d+(\.\d+)?\s*[wdhm]`)
+
+type WorklogInput struct {
+	// TimeSpent uses Jira's duration format like "1h 30m" or "2d".
+	TimeSpent string
+	// Started defaults to now if left empty.
+	Started time.Time
+	Comment string
+}
+
+// normalizeTimeSpent validates a duration and brings it into the
+// "1h 30m" form Jira expects, so "1h30m" can be used as well.
+func normalizeTimeSpent(timeSpent string) (string, error) {
+	if !timeSpentRegEx.MatchString(timeSpent) {
+		return "", fmt.Errorf("%q is not a valid duration, use units of w, d, h and m like \"1h 30m\"", timeSpent)
+	}
+
+	parts := timeSpentPartRegEx.FindAllString(timeSpent, -1)
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(p, " ", "")
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// AddWorklog books time on an issue.
+func (c *Client) AddWorklog(ctx context.Context, key string, input WorklogInput) (jira.WorklogRecord, error) {
+	timeSpent, err := normalizeTimeSpent(input.TimeSpent)
+	if err != nil {
+		return jira.WorklogRecord{}, err
+	}
+
+	started := input.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+
+	worklog := struct {
+		TimeSpent string      `json:"timeSpent"`
+		Started   jira.Time   `json:"started"`
+		Comment   interface{} `json:"comment,omitempty"`
+	}{
+		TimeSpent: timeSpent,
+		Started:   jira.Time(started),
+	}
+	if input.Comment != "" {
+		worklog.Comment = c.richText(ctx, input.Comment)
+	}
+
+	body, err := json.Marshal(worklog)
+	if err != nil {
+		return jira.WorklogRecord{}, fmt.Errorf("failed to marshal worklog: %w", err)
+	}
+
+	b, err := c.callAPI(ctx, http.MethodPost, "issue/"+key+"/worklog", nil, bytes.NewBuffer(body))
+	if err != nil {
+		return jira.WorklogRecord{}, fmt.Errorf("failed to log time on %s: %w", key, err)
+	}
+
+	var record jira.WorklogRecord
+	err = c.unmarshalWorklog(ctx, b, &record)
+	if err != nil {
+		return jira.WorklogRecord{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return record, nil
+}
+
+// ListWorklogs returns all worklogs of an issue, paging through them if
+// the server doesn't hand them out in one go.
+func (c *Client) ListWorklogs(ctx context.Context, key string) ([]jira.WorklogRecord, error) {
+	worklogs := make([]jira.WorklogRecord, 0)
+
+	for {
+		params := url.Values{}
+		params.Set("startAt", strconv.Itoa(len(worklogs)))
+
+		b, err := c.callAPI(ctx, http.MethodGet, "issue/"+key+"/worklog", params, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worklogs of %s: %w", key, err)
+		}
+
+		var page struct {
+			Total    int               `json:"total"`
+			Worklogs []json.RawMessage `json:"worklogs"`
+		}
+		err = json.Unmarshal(b, &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		for _, raw := range page.Worklogs {
+			var record jira.WorklogRecord
+			err = c.unmarshalWorklog(ctx, raw, &record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal worklog: %w", err)
+			}
+			worklogs = append(worklogs, record)
+		}
+
+		if len(page.Worklogs) == 0 || len(worklogs) >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
+
+// unmarshalWorklog decodes a worklog, rendering an ADF comment to text.
+func (c *Client) unmarshalWorklog(ctx context.Context, b []byte, record *jira.WorklogRecord) error {
+	if c.UsesADF(ctx) {
+		var fields map[string]json.RawMessage
+		err := json.Unmarshal(b, &fields)
+		if err != nil {
+			return err
+		}
+
+		if raw, ok := fields["comment"]; ok {
+			fields["comment"], err = flattenDocument(raw)
+			if err != nil {
+				return err
+			}
+		}
+
+		b, err = json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(b, record)
+}
+
+func (c *Client) DeleteWorklog(ctx context.Context, key, id string) error {
+	_, err := c.callAPI(ctx, http.MethodDelete, "issue/"+key+"/worklog/"+id, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete worklog %s on %s: %w", id, key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package jiwa
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTimeSpent(t *testing.T) {
+	testData := []struct {
+		Name       string
+		InDuration string
+		OutString  string
+		OutErrored bool
+	}{
+		{Name: "AlreadyNormalized", InDuration: "1h 30m", OutString: "1h 30m"},
+		{Name: "Compact", InDuration: "1h30m", OutString: "1h 30m"},
+		{Name: "SpacesBeforeUnits", InDuration: "2 d 4 h", OutString: "2d 4h"},
+		{Name: "Fractions", InDuration: "1.5h", OutString: "1.5h"},
+		{Name: "Weeks", InDuration: "1w", OutString: "1w"},
+		{Name: "GoDurationSeconds", InDuration: "90s", OutErrored: true},
+		{Name: "NoUnit", InDuration: "90", OutErrored: true},
+		{Name: "Empty", InDuration: "", OutErrored: true},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			result, err := normalizeTimeSpent(td.InDuration)
+
+			assert.Equal(t, td.OutErrored, err != nil)
+			assert.Equal(t, td.OutString, result)
+		})
+	}
+}
+
+func TestClient_AddWorklog(t *testing.T) {
+	testData := []struct {
+		Name         string
+		InAPIVersion string
+		OutBody      string
+		OutResponse  string
+	}{
+		{
+			Name:         "Version2SendsPlainText",
+			InAPIVersion: "2",
+			OutBody:      `{"timeSpent":"1h 30m","started":"2024-01-02T09:00:00.000+0000","comment":"fixed *it*"}`,
+			OutResponse:  `{"id":"100","timeSpent":"1h 30m","comment":"fixed *it*"}`,
+		},
+		{
+			Name:         "Version3SendsDocument",
+			InAPIVersion: "3",
+			OutBody:      `{"timeSpent":"1h 30m","started":"2024-01-02T09:00:00.000+0000","comment":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"fixed "},{"type":"text","text":"it","marks":[{"type":"em"}]}]}]}}`,
+			OutResponse:  `{"id":"100","timeSpent":"1h 30m","comment":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"fixed "},{"type":"text","text":"it","marks":[{"type":"em"}]}]}]}}`,
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/rest/api/"+td.InAPIVersion+"/issue/JIWA-1/worklog", r.URL.Path)
+
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, td.OutBody, string(body))
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(td.OutResponse))
+			})
+			client.APIVersion = td.InAPIVersion
+
+			record, err := client.AddWorklog(context.Background(), "JIWA-1", WorklogInput{
+				TimeSpent: "1h30m",
+				Started:   time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+				Comment:   "fixed *it*",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "100", record.ID)
+			assert.Equal(t, "fixed *it*", record.Comment)
+		})
+	}
+}
+
+func TestClient_ListWorklogsADF(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/JIWA-1/worklog", r.URL.Path)
+
+		// one worklog per page to make the client page through them
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		id := strconv.Itoa(100 + startAt)
+		w.Write([]byte(`{"startAt":` + strconv.Itoa(startAt) + `,"total":2,"worklogs":[{"id":"` + id + `","timeSpent":"1h","comment":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"work ` + id + `"}]}]}}]}`))
+	})
+	client.APIVersion = "3"
+
+	worklogs, err := client.ListWorklogs(context.Background(), "JIWA-1")
+	assert.NoError(t, err)
+	if assert.Len(t, worklogs, 2) {
+		assert.Equal(t, "work 100", worklogs[0].Comment)
+		assert.Equal(t, "work 101", worklogs[1].Comment)
+	}
+}
@@ -0,0 +1,122 @@
+package markdown
+
+import "regexp"
+
+// inlineRule parses a single inline construct. The pattern is anchored to
+// the current position, boundary requires the match to not be glued to
+// letters or digits on either side, like `*` in `2*3*4`.
+type inlineRule struct {
+	pattern  *regexp.Regexp
+	boundary bool
+	build    func(groups []string) Inline
+}
+
+var inlineRules []inlineRule
+
+func init() {
+	// assigned in init because the link and emphasis rules recurse into
+	// ParseInline with the rules themselves
+	inlineRules = []inlineRule{
+		{
+			pattern: regexp.MustCompile("^`([^`]+)`"),
+			build:   func(g []string) Inline { return Inline{Kind: InlineCode, Text: g[1]} },
+		},
+		{
+			pattern: regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)\)`),
+			build:   func(g []string) Inline { return Inline{Kind: InlineImage, Text: g[1], URL: g[2]} },
+		},
+		{
+			pattern: regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`),
+			build: func(g []string) Inline {
+				return Inline{Kind: InlineLink, URL: g[2], Children: ParseInline(g[1])}
+			},
+		},
+		{
+			pattern:  regexp.MustCompile(`^\*\*(\S.*?)\*\*`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineStrong, Children: ParseInline(g[1])} },
+		},
+		{
+			pattern:  regexp.MustCompile(`^__(\S.*?)__`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineStrong, Children: ParseInline(g[1])} },
+		},
+		{
+			pattern:  regexp.MustCompile(`^~~(\S.*?)~~`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineStrike, Children: ParseInline(g[1])} },
+		},
+		{
+			pattern:  regexp.MustCompile(`^\*([^*\s][^*]*)\*`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineEmphasis, Children: ParseInline(g[1])} },
+		},
+		{
+			pattern:  regexp.MustCompile(`^_([^_\s][^_]*)_`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineEmphasis, Children: ParseInline(g[1])} },
+		},
+		{
+			pattern:  regexp.MustCompile(`^https?://[^\s<>()]+[^\s<>().,;:!?'"]`),
+			boundary: true,
+			build:    func(g []string) Inline { return Inline{Kind: InlineAutoLink, URL: g[0]} },
+		},
+	}
+}
+
+// ParseInline walks text and applies the first matching rule at every
+// position, text between matches becomes InlineText.
+func ParseInline(text string) []Inline {
+	nodes := make([]Inline, 0)
+	start := 0
+
+	for i := 0; i < len(text); {
+		matched := false
+		for _, r := range inlineRules {
+			loc := r.pattern.FindStringSubmatchIndex(text[i:])
+			if loc == nil {
+				continue
+			}
+
+			end := i + loc[1]
+			if r.boundary && (isWordByte(text, i-1) || isWordByte(text, end)) {
+				continue
+			}
+
+			groups := make([]string, len(loc)/2)
+			for g := range groups {
+				if loc[2*g] >= 0 {
+					groups[g] = text[i+loc[2*g] : i+loc[2*g+1]]
+				}
+			}
+
+			if start < i {
+				nodes = append(nodes, Inline{Kind: InlineText, Text: text[start:i]})
+			}
+			nodes = append(nodes, r.build(groups))
+			i = end
+			start = end
+			matched = true
+			break
+		}
+
+		if !matched {
+			i++
+		}
+	}
+
+	if start < len(text) {
+		nodes = append(nodes, Inline{Kind: InlineText, Text: text[start:]})
+	}
+
+	return nodes
+}
+
+func isWordByte(text string, pos int) bool {
+	if pos < 0 || pos >= len(text) {
+		return false
+	}
+
+	c := text[pos]
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
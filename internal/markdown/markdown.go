@@ -0,0 +1,315 @@
+// Package markdown parses the markdown people type into their editor into a
+// tree, the adf and markup packages render it for the different versions of
+// the Jira REST API.
+//
+// Only the constructs people commonly use in tickets are understood:
+// headings, emphasis, strike through, inline code, code fences, quotes,
+// lists, tables, links and images. Everything else stays plain text.
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BlockKind tells which of the fields of a Block are set.
+type BlockKind int
+
+const (
+	// BlockParagraph has Lines.
+	BlockParagraph BlockKind = iota
+	// BlockBlank is an empty line, renderers that separate blocks on their
+	// own skip it.
+	BlockBlank
+	// BlockHeading has Level and a single line in Lines.
+	BlockHeading
+	// BlockCode has Language and Code.
+	BlockCode
+	// BlockRule is a horizontal rule.
+	BlockRule
+	// BlockList has Ordered, Start and Items.
+	BlockList
+	// BlockQuote has Children.
+	BlockQuote
+	// BlockTable has Rows and Header.
+	BlockTable
+)
+
+// Block is a single block of a document.
+type Block struct {
+	Kind BlockKind
+	// Lines holds the inline content line by line, so renderers can keep
+	// the line breaks of the source.
+	Lines [][]Inline
+	Level int
+
+	Language string
+	Code     []string
+
+	Ordered bool
+	Start   int
+	Items   []Item
+
+	Children []Block
+
+	// Rows are made up of cells, the first row holds the column names if
+	// Header is set.
+	Rows   [][][]Inline
+	Header bool
+}
+
+// Item is an entry of a list, Lists holds the lists nested below it.
+type Item struct {
+	Lines [][]Inline
+	Lists []Block
+}
+
+// InlineKind tells which of the fields of an Inline are set.
+type InlineKind int
+
+const (
+	// InlineText has Text.
+	InlineText InlineKind = iota
+	// InlineCode has Text.
+	InlineCode
+	// InlineStrong has Children.
+	InlineStrong
+	// InlineEmphasis has Children.
+	InlineEmphasis
+	// InlineStrike has Children.
+	InlineStrike
+	// InlineLink has URL and Children.
+	InlineLink
+	// InlineImage has URL and the alt text in Text.
+	InlineImage
+	// InlineAutoLink is a bare URL in the text, it has URL.
+	InlineAutoLink
+)
+
+// Inline is a piece of text and the formatting applied to it.
+type Inline struct {
+	Kind     InlineKind
+	Text     string
+	URL      string
+	Children []Inline
+}
+
+var (
+	headingRegEx     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	fenceRegEx       = regexp.MustCompile("^\\s*```\\s*([\\w+-]*)\\s*$")
+	ruleRegEx        = regexp.MustCompile(`^\s*(-{3,}|\*{3,}|_{3,})\s*$`)
+	bulletItemRegEx  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedItemRegEx = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	quoteRegEx       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	tableRowRegEx    = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	tableSepRegEx    = regexp.MustCompile(`^\s*\|(\s*:?-+:?\s*\|)+\s*$`)
+)
+
+// Parse splits text into blocks, every empty line becomes a BlockBlank.
+func Parse(text string) []Block {
+	return parseBlocks(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+}
+
+func parseBlocks(lines []string) []Block {
+	blocks := make([]Block, 0)
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		blocks = append(blocks, Block{Kind: BlockParagraph, Lines: parseLines(paragraph)})
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushParagraph()
+			blocks = append(blocks, Block{Kind: BlockBlank})
+		case fenceRegEx.MatchString(line):
+			flushParagraph()
+			code := Block{Kind: BlockCode, Language: fenceRegEx.FindStringSubmatch(line)[1]}
+			for i++; i < len(lines) && !fenceRegEx.MatchString(lines[i]); i++ {
+				code.Code = append(code.Code, lines[i])
+			}
+			blocks = append(blocks, code)
+		case headingRegEx.MatchString(line):
+			flushParagraph()
+			m := headingRegEx.FindStringSubmatch(line)
+			blocks = append(blocks, Block{
+				Kind:  BlockHeading,
+				Level: len(m[1]),
+				Lines: [][]Inline{ParseInline(m[2])},
+			})
+		case ruleRegEx.MatchString(line):
+			flushParagraph()
+			blocks = append(blocks, Block{Kind: BlockRule})
+		case bulletItemRegEx.MatchString(line) || orderedItemRegEx.MatchString(line):
+			flushParagraph()
+			end := i
+			for end < len(lines) && isListLine(lines[end]) {
+				end++
+			}
+			blocks = append(blocks, parseList(lines[i:end])...)
+			i = end - 1
+		case quoteRegEx.MatchString(line):
+			flushParagraph()
+			var quoted []string
+			for ; i < len(lines) && quoteRegEx.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteRegEx.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			blocks = append(blocks, Block{Kind: BlockQuote, Children: parseBlocks(quoted)})
+		case tableRowRegEx.MatchString(line):
+			flushParagraph()
+			var rows []string
+			for ; i < len(lines) && tableRowRegEx.MatchString(lines[i]); i++ {
+				rows = append(rows, lines[i])
+			}
+			i--
+			blocks = append(blocks, parseTable(rows))
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flushParagraph()
+
+	return blocks
+}
+
+func parseLines(lines []string) [][]Inline {
+	parsed := make([][]Inline, 0, len(lines))
+	for _, line := range lines {
+		parsed = append(parsed, ParseInline(line))
+	}
+
+	return parsed
+}
+
+// isListLine reports if line belongs to a list, either as an item or as
+// an indented continuation of the item above it.
+func isListLine(line string) bool {
+	if bulletItemRegEx.MatchString(line) || orderedItemRegEx.MatchString(line) {
+		return true
+	}
+
+	return strings.TrimSpace(line) != "" && strings.HasPrefix(line, "  ")
+}
+
+type listItem struct {
+	indent  int
+	ordered bool
+	start   int
+	text    []string
+}
+
+// parseList turns the lines of one or more adjacent lists into list blocks,
+// items indented deeper than their predecessor become a nested list.
+func parseList(lines []string) []Block {
+	items := make([]listItem, 0, len(lines))
+	for _, line := range lines {
+		if m := bulletItemRegEx.FindStringSubmatch(line); m != nil {
+			items = append(items, listItem{indent: len(m[1]), text: []string{m[2]}})
+			continue
+		}
+		if m := orderedItemRegEx.FindStringSubmatch(line); m != nil {
+			start, _ := strconv.Atoi(m[2])
+			items = append(items, listItem{indent: len(m[1]), ordered: true, start: start, text: []string{m[3]}})
+			continue
+		}
+		if len(items) > 0 {
+			last := &items[len(items)-1]
+			last.text = append(last.text, strings.TrimSpace(line))
+		}
+	}
+
+	// buildList stops at items indented less than the first one, those
+	// continue the list on their own level
+	lists := make([]Block, 0, 1)
+	for pos := 0; pos < len(items); {
+		var more []Block
+		more, pos = buildList(items, pos)
+
+		if last := len(lists) - 1; last >= 0 && lists[last].Ordered == more[0].Ordered {
+			lists[last].Items = append(lists[last].Items, more[0].Items...)
+			more = more[1:]
+		}
+		lists = append(lists, more...)
+	}
+
+	return lists
+}
+
+// buildList consumes items starting at pos that share the indentation of
+// the first one and returns the resulting lists and the next position.
+func buildList(items []listItem, pos int) ([]Block, int) {
+	lists := make([]Block, 0, 1)
+	indent := items[pos].indent
+
+	for pos < len(items) && items[pos].indent >= indent {
+		item := items[pos]
+		if len(lists) == 0 || lists[len(lists)-1].Ordered != item.ordered {
+			lists = append(lists, Block{Kind: BlockList, Ordered: item.ordered, Start: item.start})
+		}
+
+		entry := Item{Lines: parseLines(item.text)}
+		pos++
+
+		if pos < len(items) && items[pos].indent > indent {
+			entry.Lists, pos = buildList(items, pos)
+		}
+
+		current := &lists[len(lists)-1]
+		current.Items = append(current.Items, entry)
+	}
+
+	return lists, pos
+}
+
+func parseTable(rows []string) Block {
+	table := Block{Kind: BlockTable}
+	table.Header = len(rows) > 1 && tableSepRegEx.MatchString(rows[1])
+
+	for _, row := range rows {
+		if tableSepRegEx.MatchString(row) {
+			continue
+		}
+
+		var cells [][]Inline
+		for _, cell := range splitCells(row) {
+			cells = append(cells, ParseInline(cell))
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table
+}
+
+// splitCells splits a table row on pipes that aren't part of a link,
+// leading and trailing pipes are dropped.
+func splitCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+
+	cells := make([]string, 0)
+	depth := 0
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '[':
+			depth++
+		case row[i] == ']' && depth > 0:
+			depth--
+		case row[i] == '|' && depth == 0:
+			cells = append(cells, strings.TrimSpace(row[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(cells, strings.TrimSpace(row[start:]))
+}
@@ -0,0 +1,127 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testData := []struct {
+		Name      string
+		InText    string
+		OutBlocks []Block
+	}{
+		{
+			Name:   "ParagraphKeepsLines",
+			InText: "first\nsecond\n\nthird",
+			OutBlocks: []Block{
+				{Kind: BlockParagraph, Lines: [][]Inline{
+					{{Kind: InlineText, Text: "first"}},
+					{{Kind: InlineText, Text: "second"}},
+				}},
+				{Kind: BlockBlank},
+				{Kind: BlockParagraph, Lines: [][]Inline{{{Kind: InlineText, Text: "third"}}}},
+			},
+		},
+		{
+			Name:   "CodeFence",
+			InText: "```go\nx := *y*\n```",
+			OutBlocks: []Block{
+				{Kind: BlockCode, Language: "go", Code: []string{"x := *y*"}},
+			},
+		},
+		{
+			Name:   "NestedLists",
+			InText: "- one\n  1. nested\n- two",
+			OutBlocks: []Block{
+				{Kind: BlockList, Items: []Item{
+					{
+						Lines: [][]Inline{{{Kind: InlineText, Text: "one"}}},
+						Lists: []Block{{Kind: BlockList, Ordered: true, Start: 1, Items: []Item{
+							{Lines: [][]Inline{{{Kind: InlineText, Text: "nested"}}}},
+						}}},
+					},
+					{Lines: [][]Inline{{{Kind: InlineText, Text: "two"}}}},
+				}},
+			},
+		},
+		{
+			Name:   "ListOutdentedAfterFirstItem",
+			InText: "  - a\n- b\n- c",
+			OutBlocks: []Block{
+				{Kind: BlockList, Items: []Item{
+					{Lines: [][]Inline{{{Kind: InlineText, Text: "a"}}}},
+					{Lines: [][]Inline{{{Kind: InlineText, Text: "b"}}}},
+					{Lines: [][]Inline{{{Kind: InlineText, Text: "c"}}}},
+				}},
+			},
+		},
+		{
+			Name:   "TableWithHeader",
+			InText: "| a | b |\n| --- | --- |\n| 1 | 2 |",
+			OutBlocks: []Block{
+				{Kind: BlockTable, Header: true, Rows: [][][]Inline{
+					{{{Kind: InlineText, Text: "a"}}, {{Kind: InlineText, Text: "b"}}},
+					{{{Kind: InlineText, Text: "1"}}, {{Kind: InlineText, Text: "2"}}},
+				}},
+			},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.OutBlocks, Parse(td.InText))
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	testData := []struct {
+		Name      string
+		InText    string
+		OutInline []Inline
+	}{
+		{
+			Name:   "NestedMarks",
+			InText: "**bold [link](https://example.com)**",
+			OutInline: []Inline{
+				{Kind: InlineStrong, Children: []Inline{
+					{Kind: InlineText, Text: "bold "},
+					{Kind: InlineLink, URL: "https://example.com", Children: []Inline{{Kind: InlineText, Text: "link"}}},
+				}},
+			},
+		},
+		{
+			Name:      "EmphasisNeedsBoundaries",
+			InText:    "2*3*4 and snake_case_name",
+			OutInline: []Inline{{Kind: InlineText, Text: "2*3*4 and snake_case_name"}},
+		},
+		{
+			Name:   "AutoLinkStopsBeforePunctuation",
+			InText: "see https://example.com/a_b_c.",
+			OutInline: []Inline{
+				{Kind: InlineText, Text: "see "},
+				{Kind: InlineAutoLink, URL: "https://example.com/a_b_c"},
+				{Kind: InlineText, Text: "."},
+			},
+		},
+		{
+			Name:   "Image",
+			InText: "![shot](shot.png)",
+			OutInline: []Inline{
+				{Kind: InlineImage, Text: "shot", URL: "shot.png"},
+			},
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.OutInline, ParseInline(td.InText))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	jiraHeadingRegEx  = regexp.MustCompile(`^\s*h([1-6])\.\s+(.*)$`)
+	jiraCodeRegEx     = regexp.MustCompile(`^\s*\{(code|noformat)(?::([^}]*))?\}\s*$`)
+	jiraQuoteRegEx    = regexp.MustCompile(`^\s*\{quote\}\s*$`)
+	jiraBqRegEx       = regexp.MustCompile(`^\s*bq\.\s+(.*)$`)
+	jiraRuleRegEx     = regexp.MustCompile(`^\s*-{4,}\s*$`)
+	jiraListRegEx     = regexp.MustCompile(`^\s*([*#]+|-)\s+(.*)$`)
+	jiraTableRowRegEx = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+)
+
+var jiraInlineRules []inlineRule
+
+func init() {
+	jiraInlineRules = []inlineRule{
+		{
+			pattern: regexp.MustCompile(`^\{\{(.+?)\}\}`),
+			replace: func(g []string) string { return "`" + g[1] + "`" },
+		},
+		{
+			pattern: regexp.MustCompile(`^!([^!|\s]+)(\|[^!]*)?!`),
+			replace: func(g []string) string { return "![](" + g[1] + ")" },
+		},
+		{
+			pattern: regexp.MustCompile(`^\[~([^\]]+)\]`),
+			replace: func(g []string) string { return "@" + g[1] },
+		},
+		{
+			pattern: regexp.MustCompile(`^\[([^|\]]+)\|([^\]]+)\]`),
+			replace: func(g []string) string {
+				return "[" + rewriteInline(g[1], jiraInlineRules) + "](" + g[2] + ")"
+			},
+		},
+		{
+			pattern: regexp.MustCompile(`^\[(https?://[^\]]+)\]`),
+			replace: func(g []string) string { return g[1] },
+		},
+		{
+			pattern:  regexp.MustCompile(`^\*([^*\s][^*]*)\*`),
+			boundary: true,
+			replace: func(g []string) string {
+				return "**" + rewriteInline(g[1], jiraInlineRules) + "**"
+			},
+		},
+		{
+			pattern:  regexp.MustCompile(`^_([^_\s][^_]*)_`),
+			boundary: true,
+			replace: func(g []string) string {
+				return "*" + rewriteInline(g[1], jiraInlineRules) + "*"
+			},
+		},
+		{
+			pattern:  regexp.MustCompile(`^-([^-\s][^-]*)-`),
+			boundary: true,
+			replace: func(g []string) string {
+				return "~~" + rewriteInline(g[1], jiraInlineRules) + "~~"
+			},
+		},
+	}
+}
+
+// ToMarkdown converts Jira wiki markup into markdown.
+func ToMarkdown(wiki string) string {
+	lines := strings.Split(strings.ReplaceAll(wiki, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	// counters for ordered items per nesting depth
+	var counters []int
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		m := jiraListRegEx.FindStringSubmatch(line)
+		if m == nil {
+			counters = nil
+		}
+
+		switch {
+		case m != nil && !jiraRuleRegEx.MatchString(line):
+			markers := m[1]
+			if markers == "-" {
+				markers = "*"
+			}
+
+			for len(counters) < len(markers) {
+				counters = append(counters, 0)
+			}
+			counters = counters[:len(markers)]
+
+			indent := ""
+			for _, parent := range markers[:len(markers)-1] {
+				if parent == '#' {
+					indent += "   "
+				} else {
+					indent += "  "
+				}
+			}
+
+			marker := "- "
+			if markers[len(markers)-1] == '#' {
+				counters[len(markers)-1]++
+				marker = strconv.Itoa(counters[len(markers)-1]) + ". "
+			}
+			out = append(out, indent+marker+rewriteInline(m[2], jiraInlineRules))
+		case jiraCodeRegEx.MatchString(line):
+			c := jiraCodeRegEx.FindStringSubmatch(line)
+			out = append(out, "```"+codeLanguage(c[2]))
+
+			closing := "{" + c[1] + "}"
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != closing; i++ {
+				out = append(out, lines[i])
+			}
+			out = append(out, "```")
+		case jiraQuoteRegEx.MatchString(line):
+			var quoted []string
+			for i++; i < len(lines) && !jiraQuoteRegEx.MatchString(lines[i]); i++ {
+				quoted = append(quoted, lines[i])
+			}
+			for _, q := range strings.Split(ToMarkdown(strings.Join(quoted, "\n")), "\n") {
+				out = append(out, strings.TrimRight("> "+q, " "))
+			}
+		case jiraBqRegEx.MatchString(line):
+			out = append(out, "> "+rewriteInline(jiraBqRegEx.FindStringSubmatch(line)[1], jiraInlineRules))
+		case jiraHeadingRegEx.MatchString(line):
+			h := jiraHeadingRegEx.FindStringSubmatch(line)
+			level, _ := strconv.Atoi(h[1])
+			out = append(out, strings.Repeat("#", level)+" "+rewriteInline(h[2], jiraInlineRules))
+		case jiraRuleRegEx.MatchString(line):
+			out = append(out, "---")
+		case jiraTableRowRegEx.MatchString(line):
+			header := strings.HasPrefix(strings.TrimSpace(line), "||")
+			sep := "|"
+			if header {
+				sep = "||"
+			}
+
+			cells := splitCells(line, sep)
+			for c := range cells {
+				cells[c] = rewriteInline(cells[c], jiraInlineRules)
+			}
+			out = append(out, "| "+strings.Join(cells, " | ")+" |")
+
+			if header {
+				out = append(out, "|"+strings.Repeat(" --- |", len(cells)))
+			}
+		default:
+			out = append(out, rewriteInline(line, jiraInlineRules))
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// codeLanguage picks the language out of the parameters of a code macro,
+// which is either just the language or a list like language=go|title=x.
+func codeLanguage(params string) string {
+	for _, p := range strings.Split(params, "|") {
+		key, value, found := strings.Cut(p, "=")
+		switch {
+		case !found && key != "":
+			return strings.TrimSpace(key)
+		case key == "language":
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
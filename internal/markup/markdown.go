@@ -0,0 +1,115 @@
+package markup
+
+import (
+	"strings"
+
+	"github.com/catouc/jiwa/internal/markdown"
+)
+
+// FromMarkdown converts markdown into Jira wiki markup.
+func FromMarkdown(md string) string {
+	return strings.Join(wikiBlocks(markdown.Parse(md)), "\n")
+}
+
+// wikiBlocks renders the blocks line by line, empty lines are kept as they
+// are because wiki markup separates paragraphs with them as well.
+func wikiBlocks(blocks []markdown.Block) []string {
+	out := make([]string, 0, len(blocks))
+
+	for _, b := range blocks {
+		switch b.Kind {
+		case markdown.BlockBlank:
+			out = append(out, "")
+		case markdown.BlockHeading:
+			out = append(out, "h"+string(rune('0'+b.Level))+". "+wikiLine(b.Lines[0]))
+		case markdown.BlockCode:
+			if b.Language != "" {
+				out = append(out, "{code:"+b.Language+"}")
+			} else {
+				out = append(out, "{code}")
+			}
+			out = append(out, b.Code...)
+			out = append(out, "{code}")
+		case markdown.BlockRule:
+			out = append(out, "----")
+		case markdown.BlockList:
+			out = append(out, wikiList(b, "")...)
+		case markdown.BlockQuote:
+			out = append(out, "{quote}")
+			out = append(out, wikiBlocks(b.Children)...)
+			out = append(out, "{quote}")
+		case markdown.BlockTable:
+			for i, row := range b.Rows {
+				sep := "|"
+				if i == 0 && b.Header {
+					sep = "||"
+				}
+
+				cells := make([]string, 0, len(row))
+				for _, cell := range row {
+					cells = append(cells, wikiLine(cell))
+				}
+				out = append(out, sep+strings.Join(cells, sep)+sep)
+			}
+		default:
+			for _, line := range b.Lines {
+				out = append(out, wikiLine(line))
+			}
+		}
+	}
+
+	return out
+}
+
+// wikiList prefixes every item with the markers of the lists it is nested
+// in, wiki markup has no continuation lines so those are joined.
+func wikiList(list markdown.Block, prefix string) []string {
+	marker := "*"
+	if list.Ordered {
+		marker = "#"
+	}
+	prefix += marker
+
+	out := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		lines := make([]string, 0, len(item.Lines))
+		for _, line := range item.Lines {
+			lines = append(lines, wikiLine(line))
+		}
+		out = append(out, prefix+" "+strings.Join(lines, " "))
+
+		for _, nested := range item.Lists {
+			out = append(out, wikiList(nested, prefix)...)
+		}
+	}
+
+	return out
+}
+
+func wikiLine(in []markdown.Inline) string {
+	b := strings.Builder{}
+
+	for _, i := range in {
+		switch i.Kind {
+		case markdown.InlineCode:
+			b.WriteString("{{" + i.Text + "}}")
+		case markdown.InlineStrong:
+			b.WriteString("*" + wikiLine(i.Children) + "*")
+		case markdown.InlineEmphasis:
+			b.WriteString("_" + wikiLine(i.Children) + "_")
+		case markdown.InlineStrike:
+			b.WriteString("-" + wikiLine(i.Children) + "-")
+		case markdown.InlineLink:
+			b.WriteString("[" + wikiLine(i.Children) + "|" + i.URL + "]")
+		case markdown.InlineImage:
+			b.WriteString("!" + i.URL + "!")
+		case markdown.InlineAutoLink:
+			// Jira links bare URLs on its own
+			b.WriteString(i.URL)
+		default:
+			b.WriteString(i.Text)
+		}
+	}
+
+	return b.String()
+}
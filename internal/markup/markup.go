@@ -0,0 +1,97 @@
+// Package markup converts between CommonMark style markdown and the wiki
+// markup that version 2 of the Jira REST API renders.
+//
+// Only the constructs people commonly use in tickets are covered:
+// headings, emphasis, strike through, inline code, code fences, quotes,
+// lists, tables, links and images. Everything else is passed through as
+// is so nothing gets lost on the way.
+package markup
+
+import (
+	"regexp"
+	"strings"
+)
+
+// inlineRule rewrites a single inline construct. The pattern is anchored
+// to the current position, boundary requires the match to not be glued to
+// letters or digits on either side, like `*` in `2*3*4`.
+type inlineRule struct {
+	pattern  *regexp.Regexp
+	boundary bool
+	replace  func(groups []string) string
+}
+
+// rewriteInline walks text and applies the first matching rule at every
+// position, text between matches is copied unchanged.
+func rewriteInline(text string, rules []inlineRule) string {
+	b := strings.Builder{}
+
+	for i := 0; i < len(text); {
+		matched := false
+		for _, r := range rules {
+			loc := r.pattern.FindStringSubmatchIndex(text[i:])
+			if loc == nil {
+				continue
+			}
+
+			end := i + loc[1]
+			if r.boundary && (isWordByte(text, i-1) || isWordByte(text, end)) {
+				continue
+			}
+
+			groups := make([]string, len(loc)/2)
+			for g := range groups {
+				if loc[2*g] >= 0 {
+					groups[g] = text[i+loc[2*g] : i+loc[2*g+1]]
+				}
+			}
+
+			b.WriteString(r.replace(groups))
+			i = end
+			matched = true
+			break
+		}
+
+		if !matched {
+			b.WriteByte(text[i])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+func isWordByte(text string, pos int) bool {
+	if pos < 0 || pos >= len(text) {
+		return false
+	}
+
+	c := text[pos]
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// splitCells splits a table row on pipes that aren't part of a link or
+// macro like [text|url], leading and trailing separators are dropped.
+func splitCells(row, sep string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, sep)
+	row = strings.TrimSuffix(row, sep)
+
+	cells := make([]string, 0)
+	depth := 0
+	start := 0
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '[' || row[i] == '{':
+			depth++
+		case (row[i] == ']' || row[i] == '}') && depth > 0:
+			depth--
+		case depth == 0 && strings.HasPrefix(row[i:], sep):
+			cells = append(cells, strings.TrimSpace(row[start:i]))
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(cells, strings.TrimSpace(row[start:]))
+}
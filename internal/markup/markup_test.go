@@ -0,0 +1,138 @@
+package markup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	testData := []struct {
+		Name    string
+		InMD    string
+		OutWiki string
+	}{
+		{
+			Name:    "Headings",
+			InMD:    "# Title\n### Sub",
+			OutWiki: "h1. Title\nh3. Sub",
+		},
+		{
+			Name:    "Emphasis",
+			InMD:    "**bold** *italic* _also italic_ ~~gone~~ `code`",
+			OutWiki: "*bold* _italic_ _also italic_ -gone- {{code}}",
+		},
+		{
+			Name:    "EmphasisNeedsBoundaries",
+			InMD:    "2*3*4 and snake_case_name",
+			OutWiki: "2*3*4 and snake_case_name",
+		},
+		{
+			Name:    "CodeIsLeftAlone",
+			InMD:    "`**not bold**`",
+			OutWiki: "{{**not bold**}}",
+		},
+		{
+			Name:    "Links",
+			InMD:    "see [the docs](https://example.com) and ![shot](shot.png)",
+			OutWiki: "see [the docs|https://example.com] and !shot.png!",
+		},
+		{
+			Name:    "NestedLists",
+			InMD:    "- one\n  - nested\n    1. deep\n- two\n\n1. first\n2. second",
+			OutWiki: "* one\n** nested\n**# deep\n* two\n\n# first\n# second",
+		},
+		{
+			Name:    "CodeFence",
+			InMD:    "```go\nx := *y*\n```\n```\nplain\n```",
+			OutWiki: "{code:go}\nx := *y*\n{code}\n{code}\nplain\n{code}",
+		},
+		{
+			Name:    "Quote",
+			InMD:    "> quoted **text**\n> more",
+			OutWiki: "{quote}\nquoted *text*\nmore\n{quote}",
+		},
+		{
+			Name:    "Table",
+			InMD:    "| Name | Link |\n| --- | --- |\n| a | [x](https://x.y) |",
+			OutWiki: "||Name||Link||\n|a|[x|https://x.y]|",
+		},
+		{
+			Name:    "Rule",
+			InMD:    "above\n---\nbelow",
+			OutWiki: "above\n----\nbelow",
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.OutWiki, FromMarkdown(td.InMD))
+		})
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	testData := []struct {
+		Name   string
+		InWiki string
+		OutMD  string
+	}{
+		{
+			Name:   "Emphasis",
+			InWiki: "*bold* _italic_ -gone- {{code}} some-hyphenated-word",
+			OutMD:  "**bold** *italic* ~~gone~~ `code` some-hyphenated-word",
+		},
+		{
+			Name:   "LinksAndMentions",
+			InWiki: "ask [~jdoe] about [the docs|https://example.com] or [https://example.com]",
+			OutMD:  "ask @jdoe about [the docs](https://example.com) or https://example.com",
+		},
+		{
+			Name:   "CodeWithParameters",
+			InWiki: "{code:language=go|title=main.go}\nfmt.Println()\n{code}\n{noformat}\n*raw*\n{noformat}",
+			OutMD:  "```go\nfmt.Println()\n```\n```\n*raw*\n```",
+		},
+		{
+			Name:   "OrderedListsAreNumbered",
+			InWiki: "# one\n## nested\n# two\n- dash",
+			OutMD:  "1. one\n   1. nested\n2. two\n- dash",
+		},
+		{
+			Name:   "BlockQuote",
+			InWiki: "bq. short quote",
+			OutMD:  "> short quote",
+		},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.OutMD, ToMarkdown(td.InWiki))
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	testData := []struct {
+		Name string
+		InMD string
+	}{
+		{Name: "Paragraphs", InMD: "Summary of the problem\n\nSecond paragraph with **bold**, *em* and `code`."},
+		{Name: "Headings", InMD: "## Steps to reproduce\n\n1. run it\n2. see it fail"},
+		{Name: "Lists", InMD: "- one\n  - nested\n- two"},
+		{Name: "Code", InMD: "```sh\njiwa list | jiwa mv done\n```"},
+		{Name: "Quote", InMD: "> quoted\n> twice"},
+		{Name: "Table", InMD: "| a | b |\n| --- | --- |\n| 1 | [x](https://x.y) |"},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, td.InMD, ToMarkdown(FromMarkdown(td.InMD)))
+		})
+	}
+}
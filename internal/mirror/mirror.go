@@ -0,0 +1,484 @@
+// Package mirror keeps a local copy of Jira projects so they can be read
+// and searched without a connection to Jira.
+package mirror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// ErrNotMirrored is returned for issues and projects that haven't been
+// synced yet.
+var ErrNotMirrored = errors.New("not mirrored, run jiwa sync first")
+
+// DefaultDir returns $XDG_DATA_HOME/jiwa/mirror, or ~/.local/share/jiwa/mirror
+// if it isn't set.
+func DefaultDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find data directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dir, "jiwa", "mirror"), nil
+}
+
+// Store is the mirror of a single Jira instance. Every project gets its own
+// directory holding one JSON file per issue, the inverted index used by
+// Grep and the time of the last sync.
+type Store struct {
+	Dir string
+}
+
+type state struct {
+	LastSync time.Time `json:"lastSync"`
+}
+
+// index maps every term to the sorted keys of the issues containing it.
+type index map[string][]string
+
+func (s *Store) projectDir(project string) string {
+	return filepath.Join(s.Dir, strings.ToUpper(project))
+}
+
+func (s *Store) issuePath(project, key string) string {
+	return filepath.Join(s.projectDir(project), "issues", key+".json")
+}
+
+// projectOf returns the project part of an issue key.
+func projectOf(key string) string {
+	project, _, _ := strings.Cut(key, "-")
+	return project
+}
+
+// LastSync returns when the project was synced last, the zero time if it
+// never was.
+func (s *Store) LastSync(project string) (time.Time, error) {
+	var st state
+	err := readJSON(filepath.Join(s.projectDir(project), "state.json"), &st)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return st.LastSync, nil
+}
+
+// Projects returns the keys of all mirrored projects.
+func (s *Store) Projects() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+
+	projects := make([]string, 0)
+	for _, e := range entries {
+		if e.IsDir() {
+			projects = append(projects, e.Name())
+		}
+	}
+
+	return projects, nil
+}
+
+// Get returns the mirrored issue, the key is matched case insensitively
+// like Jira does.
+func (s *Store) Get(key string) (jira.Issue, error) {
+	key = strings.ToUpper(key)
+
+	var issue jira.Issue
+	err := readJSON(s.issuePath(projectOf(key), key), &issue)
+	if errors.Is(err, fs.ErrNotExist) {
+		return jira.Issue{}, fmt.Errorf("%s is %w", key, ErrNotMirrored)
+	}
+	if err != nil {
+		return jira.Issue{}, err
+	}
+
+	return issue, nil
+}
+
+// Issues returns all mirrored issues of the project ordered by their key.
+func (s *Store) Issues(project string) ([]jira.Issue, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir(project), "issues"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("project %s is %w", project, ErrNotMirrored)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".json") {
+			keys = append(keys, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+
+	return s.getAll(keys)
+}
+
+func (s *Store) getAll(keys []string) ([]jira.Issue, error) {
+	sortKeys(keys)
+
+	issues := make([]jira.Issue, 0, len(keys))
+	for _, key := range keys {
+		issue, err := s.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// Grep returns the issues whose summary, description or comments contain
+// every word of the query. If project is empty all mirrored projects are
+// searched.
+func (s *Store) Grep(project, query string) ([]jira.Issue, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, errors.New("the search text needs to contain at least one word")
+	}
+
+	projects := []string{project}
+	if project == "" {
+		var err error
+		projects, err = s.Projects()
+		if err != nil {
+			return nil, err
+		}
+		if len(projects) == 0 {
+			return nil, fmt.Errorf("nothing is %w", ErrNotMirrored)
+		}
+	} else {
+		last, err := s.LastSync(project)
+		if err != nil {
+			return nil, err
+		}
+		if last.IsZero() {
+			return nil, fmt.Errorf("project %s is %w", project, ErrNotMirrored)
+		}
+	}
+
+	keys := make([]string, 0)
+	for _, p := range projects {
+		idx, err := s.loadIndex(p)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, idx.lookup(terms)...)
+	}
+
+	// the index can hold stale terms if a sync was interrupted, so the
+	// candidates are checked against the issues themselves
+	candidates, err := s.getAll(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]jira.Issue, 0, len(candidates))
+	for _, issue := range candidates {
+		if containsAll(issueTerms(issue), terms) {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, nil
+}
+
+func (s *Store) loadIndex(project string) (index, error) {
+	idx := index{}
+	err := readJSON(filepath.Join(s.projectDir(project), "index.json"), &idx)
+	if errors.Is(err, fs.ErrNotExist) {
+		return index{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// lookup returns the keys of the issues containing all terms.
+func (idx index) lookup(terms []string) []string {
+	var keys []string
+	for i, term := range terms {
+		postings := idx[term]
+		if i == 0 {
+			keys = append(keys, postings...)
+			continue
+		}
+
+		matching := keys[:0]
+		for _, key := range keys {
+			j := sort.SearchStrings(postings, key)
+			if j < len(postings) && postings[j] == key {
+				matching = append(matching, key)
+			}
+		}
+		keys = matching
+	}
+
+	return keys
+}
+
+func (idx index) add(key string, terms []string) {
+	for _, term := range terms {
+		postings := idx[term]
+		i := sort.SearchStrings(postings, key)
+		if i < len(postings) && postings[i] == key {
+			continue
+		}
+
+		postings = append(postings, "")
+		copy(postings[i+1:], postings[i:])
+		postings[i] = key
+		idx[term] = postings
+	}
+}
+
+func (idx index) remove(key string, terms []string) {
+	for _, term := range terms {
+		postings := idx[term]
+		i := sort.SearchStrings(postings, key)
+		if i == len(postings) || postings[i] != key {
+			continue
+		}
+
+		postings = append(postings[:i], postings[i+1:]...)
+		if len(postings) == 0 {
+			delete(idx, term)
+			continue
+		}
+		idx[term] = postings
+	}
+}
+
+// Batch writes issues of a project to the mirror, the index and the time
+// of the sync are only saved on Commit.
+type Batch struct {
+	store   *Store
+	project string
+	index   index
+	// put holds the keys written by this batch, for Prune.
+	put map[string]bool
+}
+
+// Batch starts updating the mirror of the project.
+func (s *Store) Batch(project string) (*Batch, error) {
+	idx, err := s.loadIndex(project)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Batch{store: s, project: strings.ToUpper(project), index: idx, put: make(map[string]bool)}, nil
+}
+
+// Put adds the issue to the mirror or replaces the previous version of it.
+func (b *Batch) Put(issue jira.Issue) error {
+	old, err := b.store.Get(issue.Key)
+	switch {
+	case err == nil:
+		b.index.remove(issue.Key, issueTerms(old))
+	case !errors.Is(err, ErrNotMirrored):
+		return err
+	}
+
+	err = writeJSON(b.store.issuePath(b.project, issue.Key), issue)
+	if err != nil {
+		return err
+	}
+
+	b.index.add(issue.Key, issueTerms(issue))
+	b.put[issue.Key] = true
+	return nil
+}
+
+// Prune removes the issues of the project that weren't put in this batch,
+// after a batch of all issues those were deleted or moved in Jira. It
+// returns the keys that were removed.
+func (b *Batch) Prune() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(b.store.projectDir(b.project), "issues"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+
+	pruned := make([]string, 0)
+	for _, e := range entries {
+		key, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || b.put[key] {
+			continue
+		}
+
+		old, err := b.store.Get(key)
+		if err != nil {
+			return pruned, err
+		}
+		b.index.remove(key, issueTerms(old))
+
+		err = os.Remove(b.store.issuePath(b.project, key))
+		if err != nil {
+			return pruned, fmt.Errorf("failed to remove %s from the mirror: %w", key, err)
+		}
+		pruned = append(pruned, key)
+	}
+
+	return pruned, nil
+}
+
+// Commit saves the index and records syncedAt as the time of the last sync.
+func (b *Batch) Commit(syncedAt time.Time) error {
+	err := writeJSON(filepath.Join(b.store.projectDir(b.project), "index.json"), b.index)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(filepath.Join(b.store.projectDir(b.project), "state.json"), state{LastSync: syncedAt})
+}
+
+// issueTerms returns the distinct terms of the summary, description and
+// comments of the issue.
+func issueTerms(issue jira.Issue) []string {
+	if issue.Fields == nil {
+		return nil
+	}
+
+	text := []string{issue.Fields.Summary, issue.Fields.Description}
+	if issue.Fields.Comments != nil {
+		for _, c := range issue.Fields.Comments.Comments {
+			text = append(text, c.Body)
+		}
+	}
+
+	return tokenize(strings.Join(text, "\n"))
+}
+
+// tokenize splits text into lower case words, dropping duplicates.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+
+	return terms
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func containsAll(haystack, needles []string) bool {
+	set := make(map[string]bool, len(haystack))
+	for _, s := range haystack {
+		set[s] = true
+	}
+
+	for _, n := range needles {
+		if !set[n] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortKeys orders issue keys by project and then numerically, so JIWA-9
+// comes before JIWA-10.
+func sortKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		pi, ni, _ := strings.Cut(keys[i], "-")
+		pj, nj, _ := strings.Cut(keys[j], "-")
+		if pi != pj {
+			return pi < pj
+		}
+
+		a, errA := strconv.Atoi(ni)
+		b, errB := strconv.Atoi(nj)
+		if errA != nil || errB != nil {
+			return ni < nj
+		}
+
+		return a < b
+	})
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeJSON replaces the file atomically so readers never see half
+// written files.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,128 @@
+package mirror
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func issue(key, summary, description string, comments ...string) jira.Issue {
+	fields := &jira.IssueFields{Summary: summary, Description: description}
+	if len(comments) != 0 {
+		fields.Comments = &jira.Comments{}
+		for _, c := range comments {
+			fields.Comments.Comments = append(fields.Comments.Comments, &jira.Comment{Body: c})
+		}
+	}
+
+	return jira.Issue{Key: key, Fields: fields}
+}
+
+func TestStore_Grep(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	batch, err := store.Batch("JIWA")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-10", "Login broken", "The VPN drops the session")))
+	assert.NoError(t, batch.Put(issue("JIWA-9", "Flaky tests", "", "Probably the VPN again, login times out")))
+	assert.NoError(t, batch.Put(issue("JIWA-11", "Update docs", "Nothing to see")))
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	testData := []struct {
+		Name    string
+		InQuery string
+		OutKeys []string
+	}{
+		{Name: "SingleWord", InQuery: "vpn", OutKeys: []string{"JIWA-9", "JIWA-10"}},
+		{Name: "AllWords", InQuery: "VPN session", OutKeys: []string{"JIWA-10"}},
+		{Name: "Comments", InQuery: "times out", OutKeys: []string{"JIWA-9"}},
+		{Name: "NoMatch", InQuery: "kubernetes", OutKeys: []string{}},
+	}
+
+	for _, td := range testData {
+		td := td
+		t.Run(td.Name, func(t *testing.T) {
+			t.Parallel()
+
+			issues, err := store.Grep("", td.InQuery)
+			assert.NoError(t, err)
+
+			keys := make([]string, 0)
+			for _, i := range issues {
+				keys = append(keys, i.Key)
+			}
+			assert.Equal(t, td.OutKeys, keys)
+		})
+	}
+}
+
+func TestBatch_PutReplacesIndexedTerms(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	batch, err := store.Batch("JIWA")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-1", "Old summary", "")))
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	batch, err = store.Batch("JIWA")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-1", "New summary", "")))
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	idx, err := store.loadIndex("JIWA")
+	assert.NoError(t, err)
+	assert.NotContains(t, idx, "old")
+	assert.Equal(t, []string{"JIWA-1"}, idx["new"])
+
+	got, err := store.Get("JIWA-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "New summary", got.Fields.Summary)
+
+	_, err = store.Get("JIWA-2")
+	assert.ErrorIs(t, err, ErrNotMirrored)
+	_, err = store.Grep("OTHER", "summary")
+	assert.ErrorIs(t, err, ErrNotMirrored)
+}
+
+func TestStore_GetIgnoresCase(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	batch, err := store.Batch("jiwa")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-1", "Login broken", "")))
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	got, err := store.Get("jiwa-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "JIWA-1", got.Key)
+}
+
+func TestBatch_Prune(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+
+	batch, err := store.Batch("JIWA")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-1", "Login broken", "")))
+	assert.NoError(t, batch.Put(issue("JIWA-2", "Login slow", "")))
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	// JIWA-2 was deleted in Jira since
+	batch, err = store.Batch("JIWA")
+	assert.NoError(t, err)
+	assert.NoError(t, batch.Put(issue("JIWA-1", "Login broken", "")))
+	pruned, err := batch.Prune()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"JIWA-2"}, pruned)
+	assert.NoError(t, batch.Commit(time.Now()))
+
+	_, err = store.Get("JIWA-2")
+	assert.ErrorIs(t, err, ErrNotMirrored)
+
+	issues, err := store.Grep("JIWA", "login")
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, "JIWA-1", issues[0].Key)
+	}
+}
@@ -0,0 +1,217 @@
+// Package oplog records what jiwa changed in Jira so it can be undone.
+package oplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxOperations is the amount of operations kept, older ones are dropped.
+const MaxOperations = 100
+
+// ErrNoOperation is returned when there is nothing left to undo.
+var ErrNoOperation = errors.New("no operation to undo")
+
+// DefaultDir returns $XDG_DATA_HOME/jiwa/undo, or ~/.local/share/jiwa/undo
+// if it isn't set.
+func DefaultDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find data directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dir, "jiwa", "undo"), nil
+}
+
+// Operation is a single run of a command that changed issues.
+type Operation struct {
+	ID      string    `json:"id"`
+	Command string    `json:"command"`
+	BaseURL string    `json:"baseURL"`
+	Time    time.Time `json:"time"`
+	Changes []Change  `json:"changes"`
+	Undone  bool      `json:"undone"`
+}
+
+// Keys returns the keys of the changed issues.
+func (o Operation) Keys() []string {
+	keys := make([]string, 0, len(o.Changes))
+	for _, c := range o.Changes {
+		keys = append(keys, c.Key)
+	}
+
+	return keys
+}
+
+// Change holds the state of an issue before a command changed it, only the
+// fields the command touched are set.
+type Change struct {
+	Key         string    `json:"key"`
+	Status      *string   `json:"status,omitempty"`
+	Labels      *[]string `json:"labels,omitempty"`
+	Assignee    *string   `json:"assignee,omitempty"`
+	Summary     *string   `json:"summary,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	// RawDescription is the description as Jira sent it, it keeps the
+	// formatting of ADF documents that Description loses.
+	RawDescription json.RawMessage `json:"rawDescription,omitempty"`
+	// CommentID is the comment the command added.
+	CommentID string `json:"commentId,omitempty"`
+}
+
+// Log stores one JSON file per operation, named by its ID so they sort by
+// time. The random suffix of the ID keeps concurrent runs apart.
+type Log struct {
+	Dir string
+}
+
+// Record saves a new operation and drops the oldest ones beyond
+// MaxOperations.
+func (l *Log) Record(command, baseURL string, changes []Change) (Operation, error) {
+	now := time.Now()
+
+	suffix := make([]byte, 2)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return Operation{}, fmt.Errorf("failed to generate operation id: %w", err)
+	}
+
+	op := Operation{
+		ID:      now.UTC().Format("20060102-150405.000000") + "-" + hex.EncodeToString(suffix),
+		Command: command,
+		BaseURL: baseURL,
+		Time:    now,
+		Changes: changes,
+	}
+
+	err = l.Save(op)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	return op, l.prune()
+}
+
+// Save writes the operation, replacing an earlier version of it.
+func (l *Log) Save(op Operation) error {
+	b, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation: %w", err)
+	}
+
+	err = os.MkdirAll(l.Dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("failed to create operation log: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(l.Dir, op.ID+".json"), b, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write operation %s: %w", op.ID, err)
+	}
+
+	return nil
+}
+
+// Get returns the operation with the ID.
+func (l *Log) Get(id string) (Operation, error) {
+	var op Operation
+	b, err := os.ReadFile(filepath.Join(l.Dir, filepath.Base(id)+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Operation{}, fmt.Errorf("operation %s doesn't exist", id)
+	}
+	if err != nil {
+		return Operation{}, fmt.Errorf("failed to read operation %s: %w", id, err)
+	}
+
+	err = json.Unmarshal(b, &op)
+	if err != nil {
+		return Operation{}, fmt.Errorf("failed to unmarshal operation %s: %w", id, err)
+	}
+
+	return op, nil
+}
+
+// Last returns the latest operation that wasn't undone yet.
+func (l *Log) Last() (Operation, error) {
+	ops, err := l.List()
+	if err != nil {
+		return Operation{}, err
+	}
+
+	for i := len(ops) - 1; i >= 0; i-- {
+		if !ops[i].Undone {
+			return ops[i], nil
+		}
+	}
+
+	return Operation{}, ErrNoOperation
+}
+
+// List returns all operations, oldest first.
+func (l *Log) List() ([]Operation, error) {
+	ids, err := l.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]Operation, 0, len(ids))
+	for _, id := range ids {
+		op, err := l.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+func (l *Log) ids() ([]string, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation log: %w", err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".json") {
+			ids = append(ids, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func (l *Log) prune() error {
+	ids, err := l.ids()
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > MaxOperations {
+		err = os.Remove(filepath.Join(l.Dir, ids[0]+".json"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to drop operation %s: %w", ids[0], err)
+		}
+		ids = ids[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package oplog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	log := &Log{Dir: t.TempDir()}
+
+	_, err := log.Last()
+	assert.ErrorIs(t, err, ErrNoOperation)
+
+	status := "To Do"
+	first, err := log.Record("move", "https://jira.example.com", []Change{{Key: "JIWA-1", Status: &status}})
+	assert.NoError(t, err)
+	second, err := log.Record("comment", "https://jira.example.com", []Change{{Key: "JIWA-2", CommentID: "10001"}})
+	assert.NoError(t, err)
+
+	last, err := log.Last()
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, last.ID)
+
+	last.Undone = true
+	assert.NoError(t, log.Save(last))
+
+	last, err = log.Last()
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, last.ID)
+	assert.Equal(t, []string{"JIWA-1"}, last.Keys())
+	assert.Equal(t, "To Do", *last.Changes[0].Status)
+
+	for i := 0; i < MaxOperations; i++ {
+		_, err = log.Record("label", "https://jira.example.com", []Change{{Key: "JIWA-3"}})
+		assert.NoError(t, err)
+	}
+
+	ops, err := log.List()
+	assert.NoError(t, err)
+	assert.Len(t, ops, MaxOperations)
+}
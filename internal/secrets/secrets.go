@@ -0,0 +1,137 @@
+// Package secrets encrypts small blobs like credentials with a passphrase,
+// using scrypt to derive the key and AES-256-GCM to seal the data.
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Default scrypt parameters, the recommended interactive values.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+)
+
+// ErrWrongPassphrase is returned by Open when the data can't be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// envelope is the on-disk form of sealed data, it carries everything
+// besides the passphrase that is needed to open it again.
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts plaintext with the passphrase.
+func Seal(passphrase, plaintext []byte) ([]byte, error) {
+	env := envelope{
+		Version: 1,
+		KDF:     "scrypt",
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, 16),
+	}
+
+	_, err := rand.Read(env.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := env.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	env.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(env.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, env.additionalData())
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Open decrypts data that was sealed with the passphrase.
+func Open(passphrase, data []byte) ([]byte, error) {
+	var env envelope
+	err := json.Unmarshal(data, &env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sealed data: %w", err)
+	}
+
+	if env.Version != 1 || env.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported sealed data version %d with %q", env.Version, env.KDF)
+	}
+
+	// the parameters come from the file, anything but what Seal writes
+	// could make deriving the key take all memory or CPU there is
+	if env.N != scryptN || env.R != scryptR || env.P != scryptP {
+		return nil, fmt.Errorf("unsupported scrypt parameters N=%d r=%d p=%d", env.N, env.R, env.P)
+	}
+
+	aead, err := env.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, env.additionalData())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	return plaintext, nil
+}
+
+// IsSealed reports whether data looks like the output of Seal.
+func IsSealed(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+
+	var env envelope
+	err := json.Unmarshal(data, &env)
+	return err == nil && env.KDF != "" && len(env.Ciphertext) != 0
+}
+
+func (e envelope) aead(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, e.Salt, e.N, e.R, e.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData binds the KDF parameters to the ciphertext so they can't
+// be tampered with.
+func (e envelope) additionalData() []byte {
+	return []byte(fmt.Sprintf("jiwa v%d %s N=%d r=%d p=%d", e.Version, e.KDF, e.N, e.R, e.P))
+}
@@ -0,0 +1,38 @@
+package secrets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealOpen(t *testing.T) {
+	sealed, err := Seal([]byte("correct horse"), []byte(`{"password":"hunter2"}`))
+	assert.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "hunter2")
+
+	plaintext, err := Open([]byte("correct horse"), sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"hunter2"}`, string(plaintext))
+
+	_, err = Open([]byte("battery staple"), sealed)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	assert.False(t, IsSealed([]byte(`{"accessToken":"abc"}`)))
+}
+
+func TestOpenRejectsForeignParameters(t *testing.T) {
+	sealed, err := Seal([]byte("correct horse"), []byte("secret"))
+	assert.NoError(t, err)
+
+	var env envelope
+	assert.NoError(t, json.Unmarshal(sealed, &env))
+	env.N = 1 << 30
+	tampered, err := json.Marshal(env)
+	assert.NoError(t, err)
+
+	_, err = Open([]byte("correct horse"), tampered)
+	assert.ErrorContains(t, err, "unsupported scrypt parameters")
+}